@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/returnTesha/whois/internal/provider/springgrpc"
+	"github.com/returnTesha/whois/pkg/logger"
+)
+
+// analysis-stub - Spring 없이 gRPC 분석 경로를 확인하기 위한 스텁 서버
+//
+//	go run ./cmd/analysis-stub -addr :9090 -score 97
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	score := flag.Float64("score", 97, "similarity to return")
+	flag.Parse()
+
+	log := logger.Setup()
+
+	stub, err := springgrpc.StartStub(*addr, springgrpc.FixedScore(*score))
+	if err != nil {
+		log.Error("Failed to start stub", "error", err)
+		os.Exit(1)
+	}
+	log.Info("🧪 Analysis stub listening", "addr", stub.Addr(), "score", *score)
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	stub.Stop()
+	log.Info("Analysis stub stopped")
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
@@ -13,10 +16,32 @@ import (
 	"github.com/google/uuid"
 	"github.com/returnTesha/whois/config"
 	"github.com/returnTesha/whois/handler"
+	"github.com/returnTesha/whois/internal/access"
+	"github.com/returnTesha/whois/internal/archive"
+	"github.com/returnTesha/whois/internal/canvas"
+	"github.com/returnTesha/whois/internal/challenge"
+	"github.com/returnTesha/whois/internal/clientip"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/events"
+	"github.com/returnTesha/whois/internal/history"
+	"github.com/returnTesha/whois/internal/job"
+	"github.com/returnTesha/whois/internal/leaderboard"
+	"github.com/returnTesha/whois/internal/livefeed"
+	"github.com/returnTesha/whois/internal/pow"
+	"github.com/returnTesha/whois/internal/privacy"
 	"github.com/returnTesha/whois/internal/provider"
 	"github.com/returnTesha/whois/internal/provider/blockchain"
+	"github.com/returnTesha/whois/internal/provider/gemini"
+	"github.com/returnTesha/whois/internal/provider/heuristic"
+	"github.com/returnTesha/whois/internal/provider/router"
 	"github.com/returnTesha/whois/internal/provider/spring"
+	"github.com/returnTesha/whois/internal/provider/springgrpc"
+	"github.com/returnTesha/whois/internal/ratelimit"
+	"github.com/returnTesha/whois/internal/resultcache"
+	"github.com/returnTesha/whois/internal/rewardpolicy"
+	"github.com/returnTesha/whois/internal/siwe"
 	"github.com/returnTesha/whois/internal/usecase"
+	"github.com/returnTesha/whois/internal/useragent"
 	"github.com/returnTesha/whois/pkg/logger"
 )
 
@@ -42,7 +67,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	app := setupServer(cfg, providers, logger)
+	app, err := setupServer(cfg, providers, logger)
+	if err != nil {
+		logger.Error("Failed to setup server", "error", err)
+		os.Exit(1)
+	}
 	port := fmt.Sprintf(":%d", cfg.App.Port)
 
 	if err := app.Listen(port); err != nil {
@@ -99,10 +128,32 @@ func setupProviders(cfg *config.Config, logger *slog.Logger) (map[string]provide
 	registry := provider.NewRegistry()
 
 	if cfg.Spring.Enabled {
-		springProvider := spring.NewProvider(cfg.Spring, logger)
-		registry.Register(springProvider)
+		switch cfg.Spring.Transport {
+		case "grpc":
+			grpcProvider, err := springgrpc.NewProvider(cfg.Spring, logger)
+			if err != nil {
+				return nil, err
+			}
+			registry.Register(grpcProvider)
+		case "", "rest":
+			springProvider := spring.NewProvider(cfg.Spring, logger)
+			registry.Register(springProvider)
+		default:
+			return nil, fmt.Errorf("unknown spring transport: %s", cfg.Spring.Transport)
+		}
 	}
 
+	if cfg.Gemini.Enabled {
+		geminiProvider, err := gemini.NewProvider(cfg.Gemini, logger)
+		if err != nil {
+			return nil, err
+		}
+		registry.Register(geminiProvider)
+	}
+
+	// 로컬 추정기는 외부 의존성이 없어 항상 등록합니다. (analyzer.fallback으로 지정해 사용)
+	registry.Register(heuristic.NewProvider(logger))
+
 	if cfg.Polygon.Enabled {
 		polygonProvider := blockchain.NewPolygonProvider(cfg.Polygon, logger)
 		registry.Register(polygonProvider)
@@ -118,9 +169,20 @@ func setupProviders(cfg *config.Config, logger *slog.Logger) (map[string]provide
 	return providers, nil
 }
 
-func setupServer(cfg *config.Config, providers map[string]provider.Provider, logger *slog.Logger) *fiber.App {
+func setupServer(cfg *config.Config, providers map[string]provider.Provider, logger *slog.Logger) (*fiber.App, error) {
+	var normalizer *canvas.Normalizer
+	bodyLimit := 0 // fiber 기본값(4MB)
+	if cfg.Image.Enabled {
+		normalizer = canvas.NewNormalizer(cfg.Image)
+		// base64 부풀림(4/3)과 JSON 감싸기 여유분
+		bodyLimit = normalizer.MaxBytes()*4/3 + 64<<10
+	}
+
 	app := fiber.New(fiber.Config{
-		AppName: "QuestionMark v1",
+		AppName:   "QuestionMark v1",
+		BodyLimit: bodyLimit,
+		// 헤더/경로 값을 요청이 끝난 뒤(비동기 기록, 분석 작업)에도 쓰므로 복사본을 받습니다.
+		Immutable: true,
 	})
 
 	origins := os.Getenv("ALLOWED_ORIGINS")
@@ -131,36 +193,240 @@ func setupServer(cfg *config.Config, providers map[string]provider.Provider, log
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     origins,
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-API-Key, X-Admin-Token, X-PoW-Challenge, X-PoW-Nonce",
 		AllowMethods:     "GET, POST, OPTIONS, PUT, DELETE",
 		AllowCredentials: true,
 	}))
 
+	resolver, err := clientip.NewResolver(cfg.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	app.Use(resolver.Middleware())
+
 	app.Use(func(c *fiber.Ctx) error {
 		traceID := uuid.New().String()
 		c.Locals("traceID", traceID)
 		c.Set("X-Trace-ID", traceID)
+		c.Set("Accept-CH", useragent.AcceptCH)
 		return c.Next()
 	})
 
-	springProv := providers["spring"]
-	if springProv == nil {
-		logger.Error("❌ [spring] provider not found in registry!")
-		// 여기서 패닉을 내거나 기본 설정을 할 수 있습니다.
+	// 설정 오류는 백그라운드 작업(버스, 작업 큐, 제한 저장)을 띄우기 전에 잡습니다.
+	var siweService *siwe.Service
+	if cfg.SIWE.Enabled {
+		siweService, err = siwe.NewService(cfg.SIWE, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var powService *pow.Service
+	if cfg.PoW.Enabled {
+		powService, err = pow.NewService(cfg.PoW, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	analyzerRouter, err := router.New(cfg.Analyzer, providers, logger)
+	if err != nil {
+		return nil, err
+	}
+	analyzerName := analyzerRouter.GetName()
+	var analyzerProv provider.Provider = analyzerRouter
+	if fallbackName := cfg.Analyzer.Fallback; fallbackName != "" && fallbackName != analyzerName {
+		fallbackProv := providers[fallbackName]
+		if fallbackProv == nil {
+			return nil, fmt.Errorf("fallback analyzer provider [%s] not found in registry", fallbackName)
+		}
+		analyzerProv = provider.WithFallback(analyzerProv, fallbackProv, logger)
+		logger.Info("Analyzer fallback enabled", "fallback", fallbackName, "reward", cfg.Analyzer.FallbackReward)
 	}
 
 	polygonProv := providers["polygon"]
 
-	drawingUsecase := usecase.NewDrawingUsecase(springProv, polygonProv, logger)
+	anonymizer, err := privacy.NewAnonymizer(cfg.Privacy)
+	if err != nil {
+		return nil, err
+	}
+
+	historyDir := cfg.Retention.Dir
+	if historyDir == "" {
+		historyDir = history.DefaultDir
+	}
+	var archiveDirs []string
+	if cfg.Retention.ArchiveDir != "" {
+		archiveDirs = append(archiveDirs, cfg.Retention.ArchiveDir)
+	}
+	var chain *history.Chain
+	if cfg.Integrity.Enabled {
+		chain, err = history.NewChain(cfg.Integrity.SigningKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	store := history.NewStore(historyDir, archiveDirs, chain, logger)
+	if cfg.Retention.Enabled {
+		// 삭제 요청과 겹치지 않도록 Store의 잠금을 함께 씁니다.
+		history.NewRetentionManager(cfg.Retention, store, logger).Start(context.Background())
+		logger.Info("History retention manager started", "dir", historyDir)
+	}
+
+	var drawings *archive.Store
+	if cfg.Archive.Enabled {
+		drawings = archive.NewStore(cfg.Archive.Dir)
+	}
+	var cache *resultcache.Cache
+	if cfg.Cache.Enabled {
+		cache = resultcache.New(cfg.Cache, logger)
+		cache.Start(context.Background())
+	}
+	var policy *rewardpolicy.Policy
+	if cfg.Reward.Enabled {
+		policy = rewardpolicy.New(cfg.Reward, logger)
+		var recent []domain.AnalysisHistory
+		if err := store.Scan(func(h domain.AnalysisHistory) bool {
+			if h.PHash != "" && rewardpolicy.Paid(h.RewardDecision) {
+				recent = append(recent, h)
+			}
+			return true
+		}); err != nil {
+			logger.Error("Failed to load reward history", "error", err)
+		}
+		policy.Load(recent)
+	}
+
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		limiter, err = ratelimit.New(cfg.RateLimit, anonymizer, logger)
+		if err != nil {
+			return nil, err
+		}
+		limiter.Start(context.Background())
+	}
+
+	challenges, err := challenge.NewCatalog(cfg.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := events.NewBus(cfg.Events)
+	bus.Start(context.Background())
+
+	var feed *livefeed.Hub
+	if cfg.LiveFeed.Enabled {
+		feed = livefeed.NewHub(cfg.LiveFeed, logger)
+	}
+
+	drawingUsecase := usecase.NewDrawingUsecase(analyzerProv, polygonProv, store, anonymizer, normalizer, drawings, cache, policy, limiter, challenges, bus, feed, cfg.Analyzer.FallbackReward, logger)
+	privacyUsecase := usecase.NewPrivacyUsecase(store, anonymizer, cfg.Privacy.ImageDir, drawings, logger)
 
 	drawingHandler := handler.DrawingHandler{
 		Usecase: drawingUsecase,
 	}
+	jobs := job.NewManager(cfg.Jobs, logger)
+	jobs.Start(context.Background())
+	jobHandler := handler.JobHandler{
+		Usecase: drawingUsecase,
+		Jobs:    jobs,
+	}
+	adminHandler := handler.AdminHandler{
+		Privacy:  privacyUsecase,
+		Drawings: drawings,
+	}
+	eventsHandler := handler.EventsHandler{
+		Bus:         bus,
+		Heartbeat:   15 * time.Second,
+		MaxDuration: 5 * time.Minute,
+	}
+	if cfg.Events.HeartbeatSec > 0 {
+		eventsHandler.Heartbeat = time.Duration(cfg.Events.HeartbeatSec) * time.Second
+	}
+	if cfg.Events.MaxStreamSec > 0 {
+		eventsHandler.MaxDuration = time.Duration(cfg.Events.MaxStreamSec) * time.Second
+	}
+	challengeHandler := handler.ChallengeHandler{
+		Catalog: challenges,
+	}
+	healthHandler := handler.HealthHandler{
+		Providers: providers,
+	}
 
-	logger.Info("Successfully set [spring] drawing handler")
+	logger.Info("Successfully set drawing handler", "analyzer", analyzerName, "routes", analyzerRouter.Routes())
 
 	api := app.Group("/api/go/v1")
-	api.Post("/analyze", drawingHandler.AnalyzeQuestionMark)
+	if siweService != nil {
+		// 로그인한 요청이면 지갑 주소를 붙여둡니다. (로그인하지 않아도 통과)
+		api.Use(siweService.Middleware())
+
+		authHandler := handler.AuthHandler{SIWE: siweService}
+		login := api.Group("/auth")
+		login.Get("/nonce", authHandler.Nonce)
+		login.Post("/verify", authHandler.Verify)
+		login.Get("/session", authHandler.Session)
+		login.Post("/logout", authHandler.Logout)
+	}
+	// 지갑 세션 뒤에 붙여야 로그인한 지갑을 player로 볼 수 있습니다.
+	auth, err := access.NewAuthenticator(cfg.Auth, cfg.Admin.Token, logger)
+	if err != nil {
+		return nil, err
+	}
+	api.Use(auth.Middleware())
+	accessHandler := handler.AccessHandler{Auth: auth}
+	api.Get("/whoami", accessHandler.WhoAmI)
+
+	// 분석기 호출 비용이 드는 요청만 제한합니다. (지갑 세션 뒤에 붙여야 세션/지갑 키를 씁니다)
+	analyzeLimit := func(c *fiber.Ctx) error { return c.Next() }
+	if limiter != nil {
+		analyzeLimit = limiter.Middleware(ratelimit.RuleAnalysis)
+	}
+	analyzeGuards := []fiber.Handler{analyzeLimit}
+	if powService != nil {
+		powHandler := handler.PoWHandler{PoW: powService}
+		api.Get("/pow", powHandler.IssueChallenge)
+		// 제한에 걸린 요청은 난이도 계산에 넣지 않도록 요청 제한 뒤에 둡니다.
+		analyzeGuards = append(analyzeGuards, powService.Middleware())
+	}
+	api.Post("/analyze", append(slices.Clip(analyzeGuards), drawingHandler.AnalyzeDrawing)...)
+	api.Post("/jobs", append(slices.Clip(analyzeGuards), jobHandler.SubmitAnalysis)...)
+	api.Get("/jobs/:id", jobHandler.GetJob)
+	api.Get("/events/:traceID", eventsHandler.Stream)
+	api.Get("/challenges", challengeHandler.ListChallenges)
+	api.Get("/challenge/today", challengeHandler.Today)
+	if cfg.Board.Enabled {
+		board, err := leaderboard.New(cfg.Board, store, logger)
+		if err != nil {
+			return nil, err
+		}
+		leaderboardHandler := handler.LeaderboardHandler{Board: board}
+		api.Get("/leaderboard", leaderboardHandler.GetLeaderboard)
+	}
+	if feed != nil {
+		feedHandler := handler.FeedHandler{
+			Hub:     feed,
+			Ping:    time.Duration(cfg.LiveFeed.PingSec) * time.Second,
+			Origins: splitOrigins(origins),
+		}
+		api.Get("/feed", feedHandler.Join, feedHandler.Stream())
+	}
+	api.Get("/health", healthHandler.Health)
+	api.Get("/metrics", auth.Require(access.RoleAnalyst), healthHandler.Metrics)
+
+	admin := api.Group("/admin", auth.Require(access.RoleAdmin))
+	admin.Post("/erase", adminHandler.EraseHistory)
+	admin.Get("/drawings/:hash", adminHandler.GetDrawing)
+	admin.Post("/tokens", accessHandler.IssueToken)
 
-	return app
+	return app, nil
+}
+
+// splitOrigins - "a, b,c" 형식의 ALLOWED_ORIGINS를 목록으로
+func splitOrigins(origins string) []string {
+	var out []string
+	for _, o := range strings.Split(origins, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			out = append(out, o)
+		}
+	}
+	return out
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/history"
+)
+
+// verify - 히스토리 해시 체인을 처음부터 따라가며 처음 끊어진 지점을 보고합니다.
+//
+//	go run ./cmd/verify -config config/config.toml
+func main() {
+	configPath := flag.String("config", "config/config.toml", "config file path")
+	envPath := flag.String("env", ".env", "env file path (optional)")
+	dir := flag.String("dir", "", "history directory (default: retention.dir)")
+	flag.Parse()
+
+	// .env가 없어도 환경 변수만으로 동작하도록 에러는 무시합니다.
+	_ = godotenv.Load(*envPath)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ load config:", err)
+		os.Exit(2)
+	}
+
+	chain, err := history.NewChain(cfg.Integrity.SigningKey)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		os.Exit(2)
+	}
+
+	dirs := []string{*dir}
+	if *dir == "" {
+		dirs[0] = cfg.Retention.Dir
+		if dirs[0] == "" {
+			dirs[0] = history.DefaultDir
+		}
+	}
+	if cfg.Retention.ArchiveDir != "" {
+		dirs = append(dirs, cfg.Retention.ArchiveDir)
+	}
+
+	report, err := chain.Verify(dirs...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ verify:", err)
+		os.Exit(2)
+	}
+
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+
+	if !report.OK() {
+		fmt.Fprintln(os.Stderr, "❌ chain broken at", report.Broken)
+		os.Exit(1)
+	}
+	fmt.Fprintln(os.Stderr, "✅ chain intact")
+}
@@ -9,10 +9,30 @@ import (
 )
 
 type Config struct {
-	App     AppConfig     `toml:"app"`
-	Spring  SpringConfig  `toml:"spring"`
-	Polygon PolygonConfig `toml:"polygon"`
-	Log     LogConfig     `toml:"log"`
+	App       AppConfig          `toml:"app"`
+	Spring    SpringConfig       `toml:"spring"`
+	Gemini    GeminiConfig       `toml:"gemini"`
+	Analyzer  AnalyzerConfig     `toml:"analyzer"`
+	Polygon   PolygonConfig      `toml:"polygon"`
+	Log       LogConfig          `toml:"log"`
+	Retention RetentionConfig    `toml:"retention"`
+	Privacy   PrivacyConfig      `toml:"privacy"`
+	Admin     AdminConfig        `toml:"admin"`
+	Integrity IntegrityConfig    `toml:"integrity"`
+	Proxy     ProxyConfig        `toml:"proxy"`
+	Image     ImageConfig        `toml:"image"`
+	Archive   ArchiveConfig      `toml:"archive"`
+	Cache     CacheConfig        `toml:"cache"`
+	Reward    RewardPolicyConfig `toml:"reward_policy"`
+	Jobs      JobConfig          `toml:"jobs"`
+	Events    EventsConfig       `toml:"events"`
+	LiveFeed  LiveFeedConfig     `toml:"live_feed"`
+	Board     LeaderboardConfig  `toml:"leaderboard"`
+	Challenge ChallengeConfig    `toml:"challenge"`
+	SIWE      SIWEConfig         `toml:"siwe"`
+	Auth      AuthConfig         `toml:"auth"`
+	RateLimit RateLimitConfig    `toml:"rate_limit"`
+	PoW       PoWConfig          `toml:"pow"`
 }
 
 type AppConfig struct {
@@ -24,6 +44,50 @@ type SpringConfig struct {
 	Enabled bool   `toml:"enabled"`
 	BaseURL string `toml:"base_url"`
 	Timeout int    `toml:"timeout_ms"`
+
+	// rest(기본) | grpc
+	Transport string `toml:"transport"`
+	GRPCAddr  string `toml:"grpc_addr"`
+
+	// 재시도 / 서킷 브레이커
+	MaxRetries       int `toml:"max_retries"`
+	RetryBackoffMs   int `toml:"retry_backoff_ms"`
+	BreakerThreshold int `toml:"breaker_threshold"`
+	BreakerOpenSec   int `toml:"breaker_open_sec"`
+}
+
+// GeminiConfig - Spring을 거치지 않고 Gemini API를 직접 호출하는 분석기
+type GeminiConfig struct {
+	Enabled     bool    `toml:"enabled"`
+	Endpoint    string  `toml:"endpoint"`
+	Model       string  `toml:"model"`
+	APIKey      string  `toml:"api_key"`
+	Prompt      string  `toml:"prompt"` // 프롬프트 템플릿. {target}에 도전의 목표 설명이 들어갑니다. 비어있으면 Spring과 같은 기본 프롬프트
+	Temperature float64 `toml:"temperature"`
+	Timeout     int     `toml:"timeout_ms"`
+
+	BreakerThreshold int `toml:"breaker_threshold"`
+	BreakerOpenSec   int `toml:"breaker_open_sec"`
+}
+
+// AnalyzerConfig - 그림 분석에 쓸 도구 이름 (spring | gemini)
+type AnalyzerConfig struct {
+	Primary string `toml:"primary"`
+
+	// A/B 실험: 트래픽 일부를 다른 분석기(또는 여러 분석기 앙상블)로 보냅니다. 나머지는 primary.
+	Routes  []AnalyzerRoute `toml:"routes"`
+	Combine string          `toml:"combine"` // 앙상블 점수 결합 방식: mean(기본) | median | min
+
+	// 주 분석기가 실패했을 때 대신 쓸 도구 (heuristic, 비어있으면 사용 안 함)
+	Fallback       string `toml:"fallback"`
+	FallbackReward bool   `toml:"fallback_reward"` // 대체 결과로도 보상을 지급할지
+}
+
+// AnalyzerRoute - percent(0~100)만큼의 요청을 providers로 보냅니다. 2개 이상이면 병렬 실행 후 점수를 결합합니다.
+type AnalyzerRoute struct {
+	Name      string   `toml:"name"` // 히스토리에 남길 실험군 이름 (비어있으면 도구 이름을 이어 붙임)
+	Providers []string `toml:"providers"`
+	Percent   float64  `toml:"percent"`
 }
 
 type PolygonConfig struct {
@@ -38,6 +102,34 @@ type LogConfig struct {
 	Level string `toml:"level"`
 }
 
+// RetentionConfig - 히스토리 파일 압축/삭제/보관 정책
+type RetentionConfig struct {
+	Enabled           bool   `toml:"enabled"`
+	Dir               string `toml:"dir"`
+	CompressAfterDays int    `toml:"compress_after_days"`
+	DeleteAfterDays   int    `toml:"delete_after_days"`
+	ArchiveDir        string `toml:"archive_dir"` // 비어있으면 삭제, 지정하면 이동
+	IntervalMinutes   int    `toml:"interval_minutes"`
+}
+
+// PrivacyConfig - 히스토리에 저장되는 IP/User-Agent 가공 방식
+type PrivacyConfig struct {
+	IPMode   string `toml:"ip_mode"` // raw | hash | truncate
+	HashKey  string `toml:"hash_key"`
+	UAMode   string `toml:"ua_mode"` // raw | generalize | drop
+	ImageDir string `toml:"image_dir"`
+}
+
+type AdminConfig struct {
+	Token string `toml:"token"`
+}
+
+// IntegrityConfig - 히스토리 해시 체인과 일일 체크포인트 서명
+type IntegrityConfig struct {
+	Enabled    bool   `toml:"enabled"`
+	SigningKey string `toml:"signing_key"`
+}
+
 // Load - 위성 수집기에서 썼던 os.Expand 방식 그대로!
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
@@ -58,3 +150,186 @@ func Load(path string) (*Config, error) {
 
 	return &cfg, nil
 }
+
+// placeholderSecrets - 예시 .env에 흔히 남는 값. 저장소를 본 누구나 알 수 있으므로 비밀 값으로 받지 않습니다.
+var placeholderSecrets = []string{"change-me", "changeme", "change_me", "secret", "password", "admin", "test", "example", "todo"}
+
+// IsPlaceholder - 비밀 값이 예시 값 그대로인지
+func IsPlaceholder(value string) bool {
+	v := strings.ToLower(strings.TrimSpace(value))
+	for _, p := range placeholderSecrets {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
+// ProxyConfig - X-Forwarded-For 등 프록시 헤더를 믿어도 되는 주소 대역
+type ProxyConfig struct {
+	TrustedCIDRs []string `toml:"trusted_cidrs"`
+	// 프록시가 직접 채우는(클라이언트 값을 덮어쓰는) 헤더 하나만 믿습니다: X-Forwarded-For(기본) | Forwarded | X-Real-IP
+	Header string `toml:"header"`
+}
+
+// ImageConfig - 분석기로 보내기 전 그림 검사/정규화 기준
+type ImageConfig struct {
+	Enabled      bool    `toml:"enabled"`
+	MaxBytes     int     `toml:"max_bytes"` // 디코딩 후 크기
+	MaxWidth     int     `toml:"max_width"`
+	MaxHeight    int     `toml:"max_height"`
+	MinInkPixels int     `toml:"min_ink_pixels"` // 이보다 적게 그리면 빈 캔버스로 봅니다.
+	MinInkRatio  float64 `toml:"min_ink_ratio"`  // 전체 픽셀 대비 잉크 비율 하한
+	OutputSize   int     `toml:"output_size"`    // 잘라낸 그림을 다시 그릴 정사각형 한 변
+	PaddingRatio float64 `toml:"padding_ratio"`  // 잉크 영역 주변 여백 (한 변 대비)
+}
+
+// ArchiveConfig - 정규화된 그림을 해시 이름으로 보관하는 저장소
+type ArchiveConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Dir     string `toml:"dir"`
+}
+
+// CacheConfig - 같은 그림의 분석 결과 재사용 (키: 정규화된 그림 해시)
+type CacheConfig struct {
+	Enabled    bool   `toml:"enabled"`
+	TTLMinutes int    `toml:"ttl_minutes"`
+	MaxEntries int    `toml:"max_entries"` // 메모리에 둘 최대 개수
+	Dir        string `toml:"dir"`         // 비어있으면 메모리에만 둡니다.
+
+	MaxDiskEntries int `toml:"max_disk_entries"` // 디스크에 둘 최대 파일 수 (넘으면 오래된 것부터 지움)
+	SweepMinutes   int `toml:"sweep_minutes"`    // 만료/초과 파일 정리 주기
+}
+
+// RewardPolicyConfig - 거의 같은 그림(지각 해시 거리 기준)으로 반복 보상받는 것을 막는 정책
+type RewardPolicyConfig struct {
+	Enabled           bool    `toml:"enabled"`
+	WindowHours       int     `toml:"window_hours"`       // 이 기간 안의 보상 기록과 비교
+	RecipientDistance int     `toml:"recipient_distance"` // 같은 수령인의 그림과 이 거리 이하면 재제출로 봄
+	RecipientAction   string  `toml:"recipient_action"`   // allow | downgrade | block
+	GlobalDistance    int     `toml:"global_distance"`    // 다른 사람 그림과 이 거리 이하면 복제로 봄
+	GlobalAction      string  `toml:"global_action"`
+	DowngradeRate     float64 `toml:"downgrade_rate"` // downgrade 시 지급 비율 (0~1)
+	MaxEntries        int     `toml:"max_entries"`
+}
+
+// JobConfig - 비동기 분석 작업 워커 풀
+type JobConfig struct {
+	Workers          int `toml:"workers"`
+	QueueSize        int `toml:"queue_size"` // 가득 차면 429
+	TimeoutSec       int `toml:"timeout_sec"`
+	ResultTTLMinutes int `toml:"result_ttl_minutes"` // 끝난 작업을 조회할 수 있는 시간
+}
+
+// EventsConfig - 진행 상황 SSE 스트림
+type EventsConfig struct {
+	RetentionMinutes int `toml:"retention_minutes"` // 끝난 스트림을 다시 볼 수 있는 시간
+	HeartbeatSec     int `toml:"heartbeat_sec"`
+	MaxStreamSec     int `toml:"max_stream_sec"` // 연결 하나를 열어두는 최대 시간
+	MaxConnections   int `toml:"max_connections"`
+	MaxPerIP         int `toml:"max_per_ip"`
+}
+
+// LiveFeedConfig - 공개 점수판용 WebSocket 실시간 피드
+type LiveFeedConfig struct {
+	Enabled        bool `toml:"enabled"`
+	MaxConnections int  `toml:"max_connections"`
+	MaxPerIP       int  `toml:"max_per_ip"`
+	Replay         int  `toml:"replay"` // 연결 직후 보내주는 최근 기록 수
+	PingSec        int  `toml:"ping_sec"`
+}
+
+// LeaderboardConfig - 히스토리 기반 순위표
+type LeaderboardConfig struct {
+	Enabled    bool    `toml:"enabled"`
+	Timezone   string  `toml:"timezone"`    // 일간/주간 경계 기준 (비우면 서버 시간대)
+	Threshold  float64 `toml:"threshold"`   // 고득점으로 세는 점수
+	RefreshSec int     `toml:"refresh_sec"` // 히스토리를 다시 읽는 주기
+	MaxLimit   int     `toml:"max_limit"`
+}
+
+// ChallengeConfig - 그릴 수 있는 도형 목록. 비어 있으면 물음표 하나만 씁니다.
+type ChallengeConfig struct {
+	Default string             `toml:"default"` // challenge를 보내지 않은 요청에 쓸 ID
+	Catalog []ChallengeDef     `toml:"catalog"`
+	Tiers   []RewardTierConfig `toml:"tiers"`
+
+	// 오늘의 도전 순환. Rotation이 비어 있으면 순환 없이 모든 도전을 받습니다.
+	Rotation     []string `toml:"rotation"`      // 날짜마다 차례로 고르는 도전 ID
+	AlwaysActive []string `toml:"always_active"` // 순환과 상관없이 항상 받는 도전
+	Timezone     string   `toml:"timezone"`      // 날짜 경계 기준 (비우면 서버 시간대)
+	Epoch        string   `toml:"epoch"`         // 순환 시작일 (YYYY-MM-DD), 이 날이 rotation[0]
+	DailyBonus   float64  `toml:"daily_bonus"`   // 오늘의 도전 보상 배율 (예: 1.5)
+}
+
+type ChallengeDef struct {
+	ID         string  `toml:"id"`
+	Name       string  `toml:"name"`
+	Symbol     string  `toml:"symbol"`
+	Prompt     string  `toml:"prompt"`    // 분석기에 넘기는 목표 설명 (예: "a question mark symbol '?'")
+	Threshold  float64 `toml:"threshold"` // 보상 기준 점수
+	RewardTier string  `toml:"reward_tier"`
+}
+
+// RewardTierConfig - 보상 등급별 지급량 범위 (토큰 단위)
+type RewardTierConfig struct {
+	Name      string `toml:"name"`
+	MinAmount int    `toml:"min_amount"`
+	MaxAmount int    `toml:"max_amount"`
+}
+
+// SIWEConfig - 지갑 서명 로그인 (EIP-4361)
+type SIWEConfig struct {
+	Enabled           bool     `toml:"enabled"`
+	Domains           []string `toml:"domains"`   // 메시지의 domain으로 허용하는 값 (프론트엔드 호스트)
+	ChainIDs          []int64  `toml:"chain_ids"` // 비우면 모든 체인 허용
+	SessionSecret     string   `toml:"session_secret"`
+	SessionTTLMinutes int      `toml:"session_ttl_minutes"`
+	NonceTTLSec       int      `toml:"nonce_ttl_sec"`
+	CookieName        string   `toml:"cookie_name"`
+	CookieSecure      bool     `toml:"cookie_secure"`
+}
+
+// AuthConfig - 운영용 API 키와 HMAC 토큰, 역할(player < analyst < admin)
+type AuthConfig struct {
+	TokenSecret     string         `toml:"token_secret"` // 비우면 토큰 발급/확인을 하지 않고 API 키만 씁니다
+	TokenTTLMinutes int            `toml:"token_ttl_minutes"`
+	Keys            []APIKeyConfig `toml:"keys"`
+}
+
+// APIKeyConfig - 고정 API 키 하나. 값이 비어 있으면(환경변수 미설정) 건너뜁니다.
+type APIKeyConfig struct {
+	Name string `toml:"name"`
+	Key  string `toml:"key"`
+	Role string `toml:"role"` // player | analyst | admin
+}
+
+// RateLimitConfig - IP/세션/지갑별 토큰 버킷. 분석 요청과 보상 자격을 따로 제한합니다.
+type RateLimitConfig struct {
+	Enabled  bool           `toml:"enabled"`
+	Store    string         `toml:"store"` // memory | file
+	File     string         `toml:"file"`  // store = "file"일 때 버킷 상태를 저장할 경로
+	FlushSec int            `toml:"flush_sec"`
+	Analysis RateRuleConfig `toml:"analysis"`
+	Reward   RateRuleConfig `toml:"reward"`
+}
+
+// RateRuleConfig - period_sec마다 limit개씩 채워지고 최대 burst개까지 쌓이는 버킷. limit이 0이면 제한하지 않습니다.
+type RateRuleConfig struct {
+	Limit     int      `toml:"limit"`
+	PeriodSec int      `toml:"period_sec"`
+	Burst     int      `toml:"burst"` // 비우면 limit
+	Keys      []string `toml:"keys"`  // ip | session | wallet (비우면 전부)
+}
+
+// PoWConfig - 해시캐시 작업 증명. 최근 분석 요청이 baseline의 두 배가 될 때마다 난이도가 1비트씩 오릅니다.
+type PoWConfig struct {
+	Enabled   bool   `toml:"enabled"`
+	Mode      string `toml:"mode"` // analyze(풀이 없으면 거절) | reward(풀이 없으면 보상만 건너뜀)
+	Secret    string `toml:"secret"`
+	TTLSec    int    `toml:"ttl_sec"`
+	BaseBits  int    `toml:"base_bits"`
+	MaxBits   int    `toml:"max_bits"`
+	WindowSec int    `toml:"window_sec"`
+	Baseline  int    `toml:"baseline"` // window_sec 동안 이만큼까지는 base_bits
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/access"
+)
+
+type AccessHandler struct {
+	Auth *access.Authenticator
+}
+
+type issueTokenRequest struct {
+	Name       string `json:"name"`
+	Role       string `json:"role"`
+	TTLMinutes int    `json:"ttl_minutes"`
+}
+
+// IssueToken - 대시보드/스크립트용 HMAC 토큰 발급 (admin 전용)
+func (h *AccessHandler) IssueToken(c *fiber.Ctx) error {
+	var req issueTokenRequest
+	if err := c.BodyParser(&req); err != nil || req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "name and role are required"})
+	}
+	role, err := access.ParseRole(req.Role)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	token, expiresAt, err := h.Auth.Issue(req.Name, role, time.Duration(req.TTLMinutes)*time.Minute)
+	if errors.Is(err, access.ErrTokensDisabled) {
+		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(fiber.Map{
+		"token":      token,
+		"role":       role.String(),
+		"expires_at": expiresAt,
+	})
+}
+
+// WhoAmI - 현재 요청의 주체와 역할
+func (h *AccessHandler) WhoAmI(c *fiber.Ctx) error {
+	p, ok := access.FromCtx(c)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	return c.JSON(fiber.Map{"name": p.Name, "role": p.Role.String(), "via": p.Via})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/archive"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/usecase"
+)
+
+type AdminHandler struct {
+	Privacy  usecase.PrivacyUsecase
+	Drawings *archive.Store
+}
+
+func (h *AdminHandler) EraseHistory(c *fiber.Ctx) error {
+	var req domain.ErasureRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.TraceID == "" && req.IPHash == "" && req.IP == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "trace_id, ip_hash or ip is required"})
+	}
+
+	report, err := h.Privacy.Erase(req)
+	if errors.Is(err, usecase.ErrInvalidErasure) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(report)
+}
+
+// GetDrawing - 보관된 그림을 해시로 내려줍니다. 내용이 해시로 고정되므로 오래 캐시해도 됩니다.
+func (h *AdminHandler) GetDrawing(c *fiber.Ctx) error {
+	if h.Drawings == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drawing archive is disabled"})
+	}
+
+	hash := c.Params("hash")
+	path, err := h.Drawings.Path(hash)
+	if errors.Is(err, archive.ErrNotFound) {
+		return c.Status(404).JSON(fiber.Map{"error": "Drawing not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Set(fiber.HeaderETag, `"`+hash+`"`)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=31536000, immutable")
+	if c.Get(fiber.HeaderIfNoneMatch) == `"`+hash+`"` {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Type("png")
+	return c.SendFile(path)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/siwe"
+)
+
+type AuthHandler struct {
+	SIWE *siwe.Service
+}
+
+type verifyRequest struct {
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+// Nonce - 서명 메시지에 넣을 nonce 발급
+func (h *AuthHandler) Nonce(c *fiber.Ctx) error {
+	nonce, err := h.SIWE.Nonce()
+	if errors.Is(err, siwe.ErrTooManyNonces) {
+		c.Set(fiber.HeaderRetryAfter, "10")
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(fiber.Map{"nonce": nonce})
+}
+
+// Verify - 서명된 EIP-4361 메시지를 확인하고 세션 쿠키를 심습니다. 쿠키를 못 쓰는 클라이언트를 위해 토큰도 돌려줍니다.
+func (h *AuthHandler) Verify(c *fiber.Ctx) error {
+	var req verifyRequest
+	if err := c.BodyParser(&req); err != nil || req.Message == "" || req.Signature == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "message and signature are required"})
+	}
+
+	token, session, err := h.SIWE.Verify(req.Message, req.Signature)
+	if errors.Is(err, siwe.ErrInvalidMessage) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, siwe.ErrInvalidSignature) {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     h.SIWE.CookieName(),
+		Value:    token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HTTPOnly: true,
+		Secure:   h.SIWE.CookieSecure(),
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.JSON(fiber.Map{"token": token, "session": session})
+}
+
+// Session - 현재 로그인한 지갑
+func (h *AuthHandler) Session(c *fiber.Ctx) error {
+	wallet := siwe.FromCtx(c)
+	if wallet == "" {
+		return c.Status(401).JSON(fiber.Map{"error": "Not signed in"})
+	}
+	return c.JSON(fiber.Map{"address": wallet})
+}
+
+// Logout - 세션 쿠키를 지웁니다. (토큰 자체는 만료까지 유효)
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	c.Cookie(&fiber.Cookie{
+		Name:     h.SIWE.CookieName(),
+		Value:    "",
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		HTTPOnly: true,
+		Secure:   h.SIWE.CookieSecure(),
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/challenge"
+)
+
+type ChallengeHandler struct {
+	Catalog *challenge.Catalog
+}
+
+// ListChallenges - 그릴 수 있는 도형 목록과 기본 도전 (오늘 받는지 active로 표시)
+func (h *ChallengeHandler) ListChallenges(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"default":    h.Catalog.Default().ID,
+		"challenges": h.Catalog.List(time.Now()),
+	})
+}
+
+// Today - 오늘의 도전. 날이 바뀌는 시각까지만 캐시하도록 합니다.
+func (h *ChallengeHandler) Today(c *fiber.Ctx) error {
+	now := time.Now()
+	daily, ok := h.Catalog.Today(now)
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Daily challenge rotation is not configured"})
+	}
+
+	maxAge := min(int(daily.EndsAt.Sub(now).Seconds()), 300)
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(maxAge))
+	return c.JSON(daily)
+}
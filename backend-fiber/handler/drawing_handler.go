@@ -1,8 +1,14 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/canvas"
+	"github.com/returnTesha/whois/internal/challenge"
+	"github.com/returnTesha/whois/internal/clientip"
 	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/provider"
 	"github.com/returnTesha/whois/internal/usecase"
 )
 
@@ -10,9 +16,10 @@ type DrawingHandler struct {
 	Usecase usecase.DrawingUsecase
 }
 
-func (h *DrawingHandler) AnalyzeQuestionMark(c *fiber.Ctx) error {
+// AnalyzeDrawing - 그림 분석 (challenge를 보내지 않으면 기본 도전인 물음표)
+func (h *DrawingHandler) AnalyzeDrawing(c *fiber.Ctx) error {
 	traceID, _ := c.Locals("traceID").(string)
-	ip := c.Get("X-Forwarded-For", c.IP())
+	ip := clientip.FromCtx(c)
 	ua := c.Get("User-Agent")
 	path := c.Path()
 
@@ -23,8 +30,27 @@ func (h *DrawingHandler) AnalyzeQuestionMark(c *fiber.Ctx) error {
 
 	result, err := h.Usecase.ProcessAndAnalyze(c, req, traceID, ip, ua, path)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.JSON(result)
 }
+
+// errorStatus - 분석 실패 원인별 HTTP 상태 코드
+func errorStatus(err error) int {
+	var invalidImage *canvas.ValidationError
+	if errors.As(err, &invalidImage) {
+		return invalidImage.Status
+	}
+	if errors.Is(err, usecase.ErrInvalidPlayer) || errors.Is(err, challenge.ErrUnknownChallenge) {
+		return fiber.StatusBadRequest
+	}
+	if errors.Is(err, challenge.ErrInactiveChallenge) {
+		return fiber.StatusConflict
+	}
+	if errors.Is(err, provider.ErrInvalidResponse) {
+		// 분석기가 쓸 수 없는 응답을 준 경우 (업스트림 문제)
+		return fiber.StatusBadGateway
+	}
+	return 500
+}
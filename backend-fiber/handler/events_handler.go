@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/clientip"
+	"github.com/returnTesha/whois/internal/events"
+)
+
+type EventsHandler struct {
+	Bus         *events.Bus
+	Heartbeat   time.Duration
+	MaxDuration time.Duration
+}
+
+// Stream - traceID 하나의 진행 상황을 SSE로 흘려보냅니다.
+// 늦게 붙어도 지금까지의 이벤트부터 다시 보내고, 마지막(final) 이벤트를 보내면 닫습니다.
+func (h *EventsHandler) Stream(c *fiber.Ctx) error {
+	traceID := c.Params("traceID")
+	if traceID == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "traceID is required"})
+	}
+
+	replay, live, cancel, err := h.Bus.Subscribe(traceID, clientip.FromCtx(c))
+	if errors.Is(err, events.ErrTooManyFromIP) {
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, events.ErrTooManyConnections) {
+		c.Set(fiber.HeaderRetryAfter, "30")
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // nginx 버퍼링 끄기
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		for _, e := range replay {
+			if writeEvent(w, e) != nil || e.Final {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(h.Heartbeat)
+		defer heartbeat.Stop()
+		deadline := time.NewTimer(h.MaxDuration)
+		defer deadline.Stop()
+
+		for {
+			select {
+			case e := <-live:
+				if writeEvent(w, e) != nil || e.Final {
+					return
+				}
+			case <-heartbeat.C:
+				// 프록시가 유휴 연결을 끊지 않도록 주석 줄을 보냅니다. 쓰기 실패 = 클라이언트가 떠남
+				fmt.Fprint(w, ": ping\n\n")
+				if w.Flush() != nil {
+					return
+				}
+			case <-deadline.C:
+				return
+			}
+		}
+	})
+	return nil
+}
+
+func writeEvent(w *bufio.Writer, e events.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Stage, data)
+	return w.Flush()
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/clientip"
+	"github.com/returnTesha/whois/internal/livefeed"
+)
+
+const (
+	feedClientKey   = "feedClient"
+	feedReplayKey   = "feedReplay"
+	feedWriteWait   = 10 * time.Second
+	feedReadLimit   = 512 // 클라이언트는 pong 말고 보낼 것이 없습니다.
+	feedDefaultPing = 30 * time.Second
+)
+
+type FeedHandler struct {
+	Hub     *livefeed.Hub
+	Ping    time.Duration
+	Origins []string // 비어 있으면 모든 Origin 허용
+}
+
+// Join - 업그레이드 전에 연결 수 한도를 확인합니다. 넘으면 WebSocket을 열지 않고 HTTP 에러로 돌려보냅니다.
+func (h *FeedHandler) Join(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(fiber.Map{"error": "WebSocket upgrade required"})
+	}
+
+	client, replay, err := h.Hub.Join(clientip.FromCtx(c))
+	if errors.Is(err, livefeed.ErrTooManyFromIP) {
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, livefeed.ErrTooManyConnections) {
+		c.Set(fiber.HeaderRetryAfter, "30")
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Locals(feedClientKey, client)
+	c.Locals(feedReplayKey, replay)
+	if err := c.Next(); err != nil {
+		// 업그레이드에 실패하면 연결 핸들러가 불리지 않으므로 여기서 자리를 돌려놓습니다.
+		h.Hub.Leave(client)
+		return err
+	}
+	return nil
+}
+
+// Stream - 최근 기록을 먼저 보내고, 이후 분석 결과를 실시간으로 보냅니다.
+func (h *FeedHandler) Stream() fiber.Handler {
+	ping := h.Ping
+	if ping <= 0 {
+		ping = feedDefaultPing
+	}
+
+	return websocket.New(func(conn *websocket.Conn) {
+		client := conn.Locals(feedClientKey).(*livefeed.Client)
+		defer h.Hub.Leave(client)
+
+		if writeFrame(conn, websocket.TextMessage, conn.Locals(feedReplayKey).([]byte)) != nil {
+			return
+		}
+
+		// 읽기: pong이 오면 기한을 늘립니다. 두 번 연속 응답이 없으면 끊긴 것으로 봅니다.
+		gone := make(chan struct{})
+		go func() {
+			defer close(gone)
+			conn.SetReadLimit(feedReadLimit)
+			conn.SetReadDeadline(time.Now().Add(2 * ping))
+			conn.SetPongHandler(func(string) error {
+				return conn.SetReadDeadline(time.Now().Add(2 * ping))
+			})
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(ping)
+		defer ticker.Stop()
+		for {
+			select {
+			case msg, ok := <-client.Messages():
+				if !ok {
+					// 허브가 느린 연결로 보고 끊었습니다.
+					writeFrame(conn, websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too slow"))
+					return
+				}
+				if writeFrame(conn, websocket.TextMessage, msg) != nil {
+					return
+				}
+			case <-ticker.C:
+				if writeFrame(conn, websocket.PingMessage, nil) != nil {
+					return
+				}
+			case <-gone:
+				return
+			}
+		}
+	}, websocket.Config{Origins: h.Origins})
+}
+
+func writeFrame(conn *websocket.Conn, messageType int, data []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(feedWriteWait))
+	return conn.WriteMessage(messageType, data)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/provider"
+	"github.com/returnTesha/whois/pkg/breaker"
+)
+
+type HealthHandler struct {
+	Providers map[string]provider.Provider
+}
+
+// Health - 도구별 상태. 하나라도 down이면 전체는 degraded로 보고합니다. (게이트웨이 자체는 살아있으므로 200)
+func (h *HealthHandler) Health(c *fiber.Ctx) error {
+	reports := h.reports()
+
+	status := "up"
+	for _, r := range reports {
+		if r.Status != "up" {
+			status = "degraded"
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"status":    status,
+		"providers": reports,
+	})
+}
+
+// Metrics - Prometheus 텍스트 형식으로 서킷 브레이커 지표를 내보냅니다.
+func (h *HealthHandler) Metrics(c *fiber.Ctx) error {
+	var b strings.Builder
+
+	b.WriteString("# HELP whois_provider_breaker_state Circuit breaker state (0=closed, 1=half_open, 2=open)\n")
+	b.WriteString("# TYPE whois_provider_breaker_state gauge\n")
+	counters := map[string][]string{}
+	for _, r := range h.reports() {
+		stats, ok := r.Detail["breaker"].(breaker.Stats)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "whois_provider_breaker_state{provider=%q} %d\n", r.Name, stateValue(stats.State))
+
+		counters["whois_provider_requests_total"] = append(counters["whois_provider_requests_total"], fmt.Sprintf("{provider=%q} %d", r.Name, stats.Requests))
+		counters["whois_provider_failures_total"] = append(counters["whois_provider_failures_total"], fmt.Sprintf("{provider=%q} %d", r.Name, stats.Failures))
+		counters["whois_provider_rejected_total"] = append(counters["whois_provider_rejected_total"], fmt.Sprintf("{provider=%q} %d", r.Name, stats.Rejected))
+		if retries, ok := r.Detail["retries"].(uint64); ok {
+			counters["whois_provider_retries_total"] = append(counters["whois_provider_retries_total"], fmt.Sprintf("{provider=%q} %d", r.Name, retries))
+		}
+	}
+
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "# TYPE %s counter\n", name)
+		for _, line := range counters[name] {
+			b.WriteString(name + line + "\n")
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4")
+	return c.SendString(b.String())
+}
+
+func (h *HealthHandler) reports() []provider.Health {
+	var reports []provider.Health
+	for _, p := range h.Providers {
+		if reporter, ok := p.(provider.HealthReporter); ok {
+			reports = append(reports, reporter.Health())
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Name < reports[j].Name })
+	return reports
+}
+
+func stateValue(state breaker.State) int {
+	switch state {
+	case breaker.StateHalfOpen:
+		return 1
+	case breaker.StateOpen:
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/clientip"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/job"
+	"github.com/returnTesha/whois/internal/usecase"
+)
+
+// errJobPanicked - panic으로 끝난 작업의 failed 이벤트에 싣는 에러 (작업 관리자의 에러 문구와 같음)
+var errJobPanicked = errors.New("internal error")
+
+type JobHandler struct {
+	Usecase usecase.DrawingUsecase
+	Jobs    *job.Manager
+}
+
+// SubmitAnalysis - 그림 검사까지만 바로 하고, 분석/보상은 작업으로 넘긴 뒤 작업 ID를 돌려줍니다. (traceID = 작업 ID)
+func (h *JobHandler) SubmitAnalysis(c *fiber.Ctx) error {
+	traceID, _ := c.Locals("traceID").(string)
+
+	var req domain.DrawingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	// 빈 캔버스 등은 대기열을 쓰기 전에 바로 거절합니다.
+	sub := h.Usecase.NewSubmission(c, req, traceID, clientip.FromCtx(c), c.Get("User-Agent"), c.Path())
+	if err := h.Usecase.Prepare(sub); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	submitted, err := h.Jobs.Submit(traceID, func(ctx context.Context, progress func(string)) (*domain.AnalysisResult, error) {
+		defer func() {
+			if r := recover(); r != nil {
+				// 분석 중 panic이 나면 final 이벤트가 나가지 않으므로 여기서 보내고, 기록/로그는 작업 관리자에게 넘깁니다.
+				h.Usecase.Reject(sub, errJobPanicked)
+				panic(r)
+			}
+		}()
+		return h.Usecase.Analyze(ctx, sub, progress)
+	})
+	if err != nil {
+		// 이미 received/validated를 보냈으므로 이벤트 구독자가 기다리지 않도록 끝을 알립니다.
+		h.Usecase.Reject(sub, err)
+	}
+	if errors.Is(err, job.ErrQueueFull) {
+		c.Set(fiber.HeaderRetryAfter, "5")
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": "Too many analyses in progress, try again shortly"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Set(fiber.HeaderLocation, "/api/go/v1/jobs/"+submitted.ID)
+	return c.Status(fiber.StatusAccepted).JSON(submitted)
+}
+
+// GetJob - 진행 상태와 (끝났으면) 분석 결과
+func (h *JobHandler) GetJob(c *fiber.Ctx) error {
+	j, ok := h.Jobs.Get(c.Params("id"))
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Job not found"})
+	}
+	if j.Status == job.StatusFailed {
+		return c.JSON(fiber.Map{
+			"id":          j.ID,
+			"status":      j.Status,
+			"stage":       j.Stage,
+			"created_at":  j.CreatedAt,
+			"finished_at": j.FinishedAt,
+			"error":       j.Error,
+			"code":        errorStatus(j.Err()),
+		})
+	}
+	return c.JSON(j)
+}
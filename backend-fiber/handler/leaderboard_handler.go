@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/leaderboard"
+)
+
+type LeaderboardHandler struct {
+	Board *leaderboard.Board
+}
+
+// GetLeaderboard - ?period=daily|weekly|all&by=best|count&limit=20
+func (h *LeaderboardHandler) GetLeaderboard(c *fiber.Ctx) error {
+	ranking, err := h.Board.Rank(c.Query("period", leaderboard.PeriodDaily), c.Query("by"), h.Board.Limit(c.QueryInt("limit")))
+	if errors.Is(err, leaderboard.ErrInvalidPeriod) || errors.Is(err, leaderboard.ErrInvalidSort) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load leaderboard"})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=30")
+	return c.JSON(ranking)
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/pow"
+)
+
+type PoWHandler struct {
+	PoW *pow.Service
+}
+
+// IssueChallenge - 분석 요청에 풀어서 보낼 작업 증명 챌린지 발급
+func (h *PoWHandler) IssueChallenge(c *fiber.Ctx) error {
+	challenge, err := h.PoW.Issue(time.Now())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(challenge)
+}
@@ -0,0 +1,250 @@
+package access
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/siwe"
+	"github.com/returnTesha/whois/pkg/hstoken"
+)
+
+// Role - 권한 단계. 높은 역할은 낮은 역할의 권한을 모두 가집니다.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RolePlayer
+	RoleAnalyst
+	RoleAdmin
+)
+
+// LocalsKey - 미들웨어가 확인한 Principal을 저장하는 fiber Locals 키
+const LocalsKey = "principal"
+
+const (
+	// tokenIssuer - 운영 토큰 표시. 같은 형식의 지갑 세션 토큰과 섞이지 않게 합니다.
+	tokenIssuer     = "whois-access"
+	defaultTokenTTL = 12 * time.Hour
+	maxTokenTTL     = 30 * 24 * time.Hour
+	minKeyLength    = 16
+)
+
+var (
+	// ErrTokensDisabled - token_secret이 없어 토큰을 발급할 수 없음
+	ErrTokensDisabled = errors.New("access tokens are disabled")
+	// ErrUnknownRole - 설정/요청의 역할 이름이 잘못됨
+	ErrUnknownRole = errors.New("unknown role")
+)
+
+func (r Role) String() string {
+	switch r {
+	case RolePlayer:
+		return "player"
+	case RoleAnalyst:
+		return "analyst"
+	case RoleAdmin:
+		return "admin"
+	}
+	return "none"
+}
+
+// ParseRole - player | analyst | admin
+func ParseRole(name string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "player":
+		return RolePlayer, nil
+	case "analyst":
+		return RoleAnalyst, nil
+	case "admin":
+		return RoleAdmin, nil
+	}
+	return RoleNone, fmt.Errorf("%w: %q", ErrUnknownRole, name)
+}
+
+// Principal - 요청을 보낸 주체. Via는 api_key | token | wallet
+type Principal struct {
+	Name string
+	Role Role
+	Via  string
+}
+
+type apiKey struct {
+	name string
+	hash [sha256.Size]byte
+	role Role
+}
+
+// Authenticator - API 키와 HMAC 토큰을 확인해 역할을 붙이고, 라우트별로 역할을 요구합니다.
+type Authenticator struct {
+	keys     []apiKey
+	secret   []byte
+	tokenTTL time.Duration
+	logger   *slog.Logger
+}
+
+// NewAuthenticator - legacyAdminToken은 예전 X-Admin-Token 값으로, 있으면 admin 키로 등록합니다.
+func NewAuthenticator(cfg config.AuthConfig, legacyAdminToken string, logger *slog.Logger) (*Authenticator, error) {
+	a := &Authenticator{
+		tokenTTL: defaultTokenTTL,
+		logger:   logger.With("layer", "access"),
+	}
+	if cfg.TokenSecret != "" {
+		if len(cfg.TokenSecret) < 32 || config.IsPlaceholder(cfg.TokenSecret) {
+			return nil, fmt.Errorf("access: token_secret must be at least 32 bytes")
+		}
+		a.secret = []byte(cfg.TokenSecret)
+	}
+	if cfg.TokenTTLMinutes > 0 {
+		a.tokenTTL = time.Duration(cfg.TokenTTLMinutes) * time.Minute
+	}
+
+	for _, k := range cfg.Keys {
+		role, err := ParseRole(k.Role)
+		if err != nil {
+			return nil, fmt.Errorf("access: key %q: %w", k.Name, err)
+		}
+		if k.Key == "" {
+			a.logger.Warn("API 키 값이 비어 있어 건너뜁니다", "name", k.Name)
+			continue
+		}
+		if err := checkKey(k.Name, k.Key); err != nil {
+			return nil, err
+		}
+		a.keys = append(a.keys, apiKey{name: k.Name, hash: sha256.Sum256([]byte(k.Key)), role: role})
+	}
+	if legacyAdminToken != "" {
+		if err := checkKey("admin-token", legacyAdminToken); err != nil {
+			return nil, err
+		}
+		a.keys = append(a.keys, apiKey{name: "admin-token", hash: sha256.Sum256([]byte(legacyAdminToken)), role: RoleAdmin})
+	}
+	return a, nil
+}
+
+// checkKey - 짧거나 예시 값 그대로인 키는 누구나 맞힐 수 있으므로 시작을 거부합니다.
+func checkKey(name, key string) error {
+	if config.IsPlaceholder(key) {
+		return fmt.Errorf("access: key %q is a placeholder value, set a random secret", name)
+	}
+	if len(key) < minKeyLength {
+		return fmt.Errorf("access: key %q must be at least %d characters", name, minKeyLength)
+	}
+	return nil
+}
+
+// Middleware - X-API-Key, X-Admin-Token 또는 Authorization: Bearer 값을 확인해 Principal을 붙입니다.
+// 자격 증명이 없으면 지갑 세션을 player로 봅니다. 막는 일은 Require가 합니다.
+func (a *Authenticator) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if p, ok := a.authenticate(c); ok {
+			c.Locals(LocalsKey, p)
+		} else if wallet := siwe.FromCtx(c); wallet != "" {
+			c.Locals(LocalsKey, Principal{Name: wallet, Role: RolePlayer, Via: "wallet"})
+		}
+		return c.Next()
+	}
+}
+
+// Require - 역할이 min 이상일 때만 통과. 자격 증명이 없으면 401, 역할이 낮으면 403
+func (a *Authenticator) Require(min Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		p, ok := FromCtx(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		}
+		if p.Role < min {
+			a.logger.Warn("권한 부족", "name", p.Name, "role", p.Role.String(), "required", min.String(), "path", c.Path())
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Forbidden", "required_role": min.String()})
+		}
+		return c.Next()
+	}
+}
+
+// Issue - 이름과 역할을 담은 HMAC 토큰을 발급합니다. ttl이 0이면 기본값을 씁니다.
+func (a *Authenticator) Issue(name string, role Role, ttl time.Duration) (string, time.Time, error) {
+	if a.secret == nil {
+		return "", time.Time{}, ErrTokensDisabled
+	}
+	if role == RoleNone {
+		return "", time.Time{}, ErrUnknownRole
+	}
+	if ttl <= 0 {
+		ttl = a.tokenTTL
+	}
+	ttl = min(ttl, maxTokenTTL)
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	token, err := hstoken.Sign(a.secret, hstoken.Claims{
+		Subject:   name,
+		Issuer:    tokenIssuer,
+		Role:      role.String(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// FromCtx - 확인된 Principal. 없으면 ok=false
+func FromCtx(c *fiber.Ctx) (Principal, bool) {
+	p, ok := c.Locals(LocalsKey).(Principal)
+	return p, ok
+}
+
+func (a *Authenticator) authenticate(c *fiber.Ctx) (Principal, bool) {
+	if key := c.Get("X-API-Key"); key != "" {
+		return a.matchKey(key)
+	}
+	if key := c.Get("X-Admin-Token"); key != "" {
+		return a.matchKey(key)
+	}
+	bearer, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || bearer == "" {
+		return Principal{}, false
+	}
+	// 점이 두 개면 토큰, 아니면 API 키로 봅니다. (지갑 세션 토큰은 서명이 달라 여기서 걸러집니다)
+	if strings.Count(bearer, ".") == 2 {
+		return a.verifyToken(bearer)
+	}
+	return a.matchKey(bearer)
+}
+
+// matchKey - 키 길이가 드러나지 않도록 해시끼리 비교하고, 모든 키를 끝까지 비교합니다.
+func (a *Authenticator) matchKey(given string) (Principal, bool) {
+	hash := sha256.Sum256([]byte(given))
+	var found *apiKey
+	for i := range a.keys {
+		if subtle.ConstantTimeCompare(hash[:], a.keys[i].hash[:]) == 1 {
+			found = &a.keys[i]
+		}
+	}
+	if found == nil {
+		return Principal{}, false
+	}
+	return Principal{Name: found.name, Role: found.role, Via: "api_key"}, true
+}
+
+func (a *Authenticator) verifyToken(token string) (Principal, bool) {
+	if a.secret == nil {
+		return Principal{}, false
+	}
+	claims, err := hstoken.Verify(a.secret, token, time.Now())
+	if err != nil || claims.Issuer != tokenIssuer {
+		return Principal{}, false
+	}
+	role, err := ParseRole(claims.Role)
+	if err != nil {
+		return Principal{}, false
+	}
+	return Principal{Name: claims.Subject, Role: role, Via: "token"}, true
+}
@@ -0,0 +1,114 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultDir - 정규화된 그림을 보관하는 기본 위치
+const DefaultDir = "/mnt/drawings"
+
+const ext = ".png"
+
+// ErrNotFound - 해당 해시의 그림이 없음
+var ErrNotFound = errors.New("drawing not found")
+
+// Store - 그림을 SHA-256 해시 이름으로 저장합니다. (ab/cd/abcd....png 로 2단계 샤딩)
+// 같은 그림은 한 번만 저장되므로 재업로드는 자연스럽게 중복 제거됩니다.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Store{dir: dir}
+}
+
+// Hash - 그림 바이트의 SHA-256 (hex)
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidHash - 64자리 소문자 hex인지 (경로 조작 방지)
+func ValidHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash[2:4], hash+ext)
+}
+
+// Put - 그림을 저장하고 해시를 돌려줍니다. 이미 있으면 쓰지 않고 created=false.
+func (s *Store) Put(data []byte) (string, bool, error) {
+	hash := Hash(data)
+	path := s.path(hash)
+
+	if info, err := os.Stat(path); err == nil && info.Size() == int64(len(data)) {
+		return hash, false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", false, fmt.Errorf("archive: %w", err)
+	}
+
+	// 쓰다가 죽어도 반쪽짜리 파일이 남지 않도록 임시 파일에 쓰고 rename 합니다.
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".*.tmp")
+	if err != nil {
+		return "", false, fmt.Errorf("archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", false, fmt.Errorf("archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", false, fmt.Errorf("archive: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", false, fmt.Errorf("archive: %w", err)
+	}
+	return hash, true, nil
+}
+
+// Path - 저장된 그림의 파일 경로
+func (s *Store) Path(hash string) (string, error) {
+	if !ValidHash(hash) {
+		return "", ErrNotFound
+	}
+	path := s.path(hash)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// Remove - 그림을 지웁니다. 없으면 false.
+func (s *Store) Remove(hash string) (bool, error) {
+	if !ValidHash(hash) {
+		return false, nil
+	}
+	err := os.Remove(s.path(hash))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
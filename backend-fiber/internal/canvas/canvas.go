@@ -0,0 +1,227 @@
+package canvas
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"image/png"
+	"math"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/pkg/dataurl"
+)
+
+// 잉크(펜 자국)로 보는 기준 - 흰 배경에 검은 펜으로 그리는 프론트엔드 캔버스 기준
+const (
+	InkLuma  = 128
+	InkAlpha = 128
+)
+
+const (
+	defaultMaxBytes     = 2 << 20
+	defaultMaxSide      = 4096
+	defaultMinInkPixels = 30
+	defaultOutputSize   = 512
+	defaultPadding      = 0.08
+)
+
+// ErrInvalidImage - 분석기로 보내기 전에 거부된 그림 (errors.Is로 확인)
+var ErrInvalidImage = errors.New("invalid drawing")
+
+// ValidationError - 거부 사유와 응답할 HTTP 상태 코드
+type ValidationError struct {
+	Status int
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidImage, e.Reason)
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidImage
+}
+
+func reject(status int, format string, args ...any) error {
+	return &ValidationError{Status: status, Reason: fmt.Sprintf(format, args...)}
+}
+
+// Stats - 정규화 전후 정보 (로그용)
+type Stats struct {
+	Width     int     `json:"width"`
+	Height    int     `json:"height"`
+	Bytes     int     `json:"bytes"`
+	InkPixels int     `json:"ink_pixels"`
+	InkRatio  float64 `json:"ink_ratio"`
+	InkExtent float64 `json:"ink_extent"` // 잉크 영역의 긴 변 / 캔버스의 짧은 변
+	Crop      string  `json:"crop"`
+	OutBytes  int     `json:"out_bytes"`
+}
+
+// Normalizer - data URL을 풀어 크기를 검사하고, 잉크 영역만 잘라 정사각 캔버스로 다시 그립니다.
+type Normalizer struct {
+	cfg config.ImageConfig
+}
+
+func NewNormalizer(cfg config.ImageConfig) *Normalizer {
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaultMaxBytes
+	}
+	if cfg.MaxWidth <= 0 {
+		cfg.MaxWidth = defaultMaxSide
+	}
+	if cfg.MaxHeight <= 0 {
+		cfg.MaxHeight = defaultMaxSide
+	}
+	if cfg.MinInkPixels <= 0 {
+		cfg.MinInkPixels = defaultMinInkPixels
+	}
+	if cfg.OutputSize <= 0 {
+		cfg.OutputSize = defaultOutputSize
+	}
+	if cfg.PaddingRatio <= 0 {
+		cfg.PaddingRatio = defaultPadding
+	}
+	return &Normalizer{cfg: cfg}
+}
+
+// MaxBytes - 디코딩 후 허용하는 이미지 크기 (요청 본문 제한 계산용)
+func (n *Normalizer) MaxBytes() int {
+	return n.cfg.MaxBytes
+}
+
+// Normalize - 검사/정규화한 PNG data URL을 돌려줍니다. 거부할 때는 *ValidationError.
+func (n *Normalizer) Normalize(imageData string) (string, Stats, error) {
+	var stats Stats
+
+	// base64는 원본보다 4/3 크므로 디코딩 전에 길이로 먼저 거릅니다.
+	if len(imageData) > n.cfg.MaxBytes*4/3+256 {
+		return "", stats, reject(413, "image larger than %d bytes", n.cfg.MaxBytes)
+	}
+	if imageData == "" {
+		return "", stats, reject(400, "image is empty")
+	}
+
+	_, raw, err := dataurl.Decode(imageData)
+	if err != nil {
+		return "", stats, reject(400, "%v", err)
+	}
+	stats.Bytes = len(raw)
+	if len(raw) > n.cfg.MaxBytes {
+		return "", stats, reject(413, "image larger than %d bytes", n.cfg.MaxBytes)
+	}
+
+	// 픽셀을 펼치기 전에 헤더만 읽어 해상도를 확인합니다. (압축 폭탄 방지)
+	header, format, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return "", stats, reject(415, "unsupported image format")
+	}
+	stats.Width, stats.Height = header.Width, header.Height
+	if header.Width > n.cfg.MaxWidth || header.Height > n.cfg.MaxHeight {
+		return "", stats, reject(413, "image %dx%d exceeds %dx%d", header.Width, header.Height, n.cfg.MaxWidth, n.cfg.MaxHeight)
+	}
+	if header.Width == 0 || header.Height == 0 {
+		return "", stats, reject(400, "image has no pixels")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return "", stats, reject(400, "decode %s: %v", format, err)
+	}
+
+	gray, bounds, inkPixels := flatten(img)
+	stats.InkPixels = inkPixels
+	stats.InkRatio = float64(inkPixels) / float64(header.Width*header.Height)
+	if inkPixels < n.cfg.MinInkPixels || stats.InkRatio < n.cfg.MinInkRatio {
+		return "", stats, reject(422, "canvas is blank or nearly blank")
+	}
+	stats.Crop = bounds.String()
+	stats.InkExtent = float64(max(bounds.Dx(), bounds.Dy())) / float64(min(header.Width, header.Height))
+
+	out := n.render(gray, bounds)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return "", stats, fmt.Errorf("canvas: encode png: %w", err)
+	}
+	stats.OutBytes = buf.Len()
+	return dataurl.Encode("image/png", buf.Bytes()), stats, nil
+}
+
+// IsInk - 불투명하고 어두운 픽셀
+func IsInk(c color.Color) bool {
+	r, g, b, a := c.RGBA()
+	if a>>8 < InkAlpha {
+		return false
+	}
+	return luma(r, g, b) < InkLuma
+}
+
+func luma(r, g, b uint32) uint32 {
+	return (299*(r>>8) + 587*(g>>8) + 114*(b>>8)) / 1000
+}
+
+// flatten - 투명 영역을 흰 배경에 합성한 흑백 이미지와 잉크 영역(bounding box), 잉크 픽셀 수
+func flatten(img image.Image) (*image.Gray, image.Rectangle, int) {
+	b := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, b.Dx(), b.Dy()))
+	minX, minY, maxX, maxY := b.Dx(), b.Dy(), -1, -1
+	ink := 0
+
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			c := img.At(b.Min.X+x, b.Min.Y+y)
+			r, g, bl, a := c.RGBA()
+			// premultiplied 값이므로 흰 배경 합성은 (1-a)*255를 더하면 됩니다.
+			v := luma(r, g, bl) + (0xff - a>>8)
+			gray.Pix[y*gray.Stride+x] = uint8(min(v, 0xff))
+
+			if IsInk(c) {
+				ink++
+				minX, minY = min(minX, x), min(minY, y)
+				maxX, maxY = max(maxX, x), max(maxY, y)
+			}
+		}
+	}
+	return gray, image.Rect(minX, minY, maxX+1, maxY+1), ink
+}
+
+// render - 잉크 영역을 가운데에 두고 여백을 붙여 OutputSize 정사각형으로 다시 그립니다.
+// 축소는 영역 평균, 확대는 가장 가까운 픽셀을 씁니다.
+func (n *Normalizer) render(src *image.Gray, ink image.Rectangle) *image.Gray {
+	size := n.cfg.OutputSize
+	dst := image.NewGray(image.Rect(0, 0, size, size))
+	for i := range dst.Pix {
+		dst.Pix[i] = 0xff
+	}
+
+	side := float64(max(ink.Dx(), ink.Dy()))
+	side += 2 * side * n.cfg.PaddingRatio
+	scale := side / float64(size) // 출력 1픽셀이 덮는 원본 픽셀 수
+	originX := float64(ink.Min.X) + float64(ink.Dx())/2 - side/2
+	originY := float64(ink.Min.Y) + float64(ink.Dy())/2 - side/2
+
+	samples := max(1, int(math.Ceil(scale)))
+	sb := src.Bounds()
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			sum, count := 0, 0
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					px := int(originX + (float64(x)+(float64(sx)+0.5)/float64(samples))*scale)
+					py := int(originY + (float64(y)+(float64(sy)+0.5)/float64(samples))*scale)
+					count++
+					if px < sb.Min.X || py < sb.Min.Y || px >= sb.Max.X || py >= sb.Max.Y {
+						sum += 0xff
+						continue
+					}
+					sum += int(src.Pix[py*src.Stride+px])
+				}
+			}
+			dst.Pix[y*dst.Stride+x] = uint8(sum / count)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,172 @@
+package challenge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+)
+
+// 설정에 도형이 없을 때 쓰는 기본 도전 (예전 물음표 전용 동작과 같음)
+const (
+	DefaultID        = "question-mark"
+	defaultThreshold = 95
+	defaultTier      = "standard"
+	defaultMinAmount = 1
+	defaultMaxAmount = 1000
+)
+
+var (
+	// ErrUnknownChallenge - 목록에 없는 challenge (400)
+	ErrUnknownChallenge = errors.New("unknown challenge")
+	// ErrInactiveChallenge - 오늘은 받지 않는 challenge (409)
+	ErrInactiveChallenge = errors.New("challenge is not active today")
+)
+
+// Tier - 보상 등급. 지급량은 MinAmount~MaxAmount 사이에서 정합니다.
+type Tier struct {
+	Name      string `json:"name"`
+	MinAmount int    `json:"min_amount"`
+	MaxAmount int    `json:"max_amount"`
+}
+
+// Challenge - 그려야 할 도형 하나
+type Challenge struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Symbol    string  `json:"symbol"`
+	Prompt    string  `json:"-"` // 분석기용 목표 설명
+	Threshold float64 `json:"threshold"`
+	Tier      Tier    `json:"reward_tier"` // 오늘의 도전이면 보너스가 반영된 범위
+
+	// 조회 시점 기준 (Resolve/List가 채움)
+	Active bool    `json:"active"`
+	Daily  bool    `json:"daily,omitempty"`
+	Bonus  float64 `json:"bonus,omitempty"`
+}
+
+// Catalog - 설정의 도형 목록과 오늘의 도전 순환
+type Catalog struct {
+	list      []Challenge
+	byID      map[string]Challenge
+	defaultID string
+	rotation  *rotation // nil이면 순환 없이 모두 활성
+}
+
+func NewCatalog(cfg config.ChallengeConfig) (*Catalog, error) {
+	tiers := map[string]Tier{
+		defaultTier: {Name: defaultTier, MinAmount: defaultMinAmount, MaxAmount: defaultMaxAmount},
+	}
+	for _, t := range cfg.Tiers {
+		if t.Name == "" {
+			return nil, fmt.Errorf("challenge: reward tier without name")
+		}
+		if t.MinAmount < 1 || t.MaxAmount < t.MinAmount {
+			return nil, fmt.Errorf("challenge: reward tier %q needs 1 <= min_amount <= max_amount", t.Name)
+		}
+		tiers[t.Name] = Tier{Name: t.Name, MinAmount: t.MinAmount, MaxAmount: t.MaxAmount}
+	}
+
+	defs := cfg.Catalog
+	if len(defs) == 0 {
+		defs = []config.ChallengeDef{{
+			ID:     DefaultID,
+			Name:   "물음표",
+			Symbol: "?",
+			Prompt: "a question mark symbol '?'",
+		}}
+	}
+
+	c := &Catalog{byID: map[string]Challenge{}, defaultID: cfg.Default}
+	for _, d := range defs {
+		id := strings.TrimSpace(d.ID)
+		if id == "" || d.Symbol == "" || d.Prompt == "" {
+			return nil, fmt.Errorf("challenge: id, symbol and prompt are required (id=%q)", d.ID)
+		}
+		if _, dup := c.byID[id]; dup {
+			return nil, fmt.Errorf("challenge: duplicate id %q", id)
+		}
+		threshold := d.Threshold
+		if threshold == 0 {
+			threshold = defaultThreshold
+		}
+		if threshold < 0 || threshold > 100 {
+			return nil, fmt.Errorf("challenge: %q threshold must be within 0~100", id)
+		}
+		tierName := d.RewardTier
+		if tierName == "" {
+			tierName = defaultTier
+		}
+		tier, ok := tiers[tierName]
+		if !ok {
+			return nil, fmt.Errorf("challenge: %q uses unknown reward tier %q", id, tierName)
+		}
+
+		ch := Challenge{ID: id, Name: d.Name, Symbol: d.Symbol, Prompt: d.Prompt, Threshold: threshold, Tier: tier}
+		c.list = append(c.list, ch)
+		c.byID[id] = ch
+	}
+
+	if c.defaultID == "" {
+		c.defaultID = c.list[0].ID
+	}
+	if _, ok := c.byID[c.defaultID]; !ok {
+		return nil, fmt.Errorf("challenge: default %q is not in the catalog", c.defaultID)
+	}
+
+	rot, err := newRotation(cfg, c.byID)
+	if err != nil {
+		return nil, err
+	}
+	c.rotation = rot
+	return c, nil
+}
+
+// Resolve - now 시점에 요청의 challenge ID를 찾습니다. 비어 있으면 기본 도전입니다.
+// 오늘 받지 않는 도전이면 ErrInactiveChallenge.
+func (c *Catalog) Resolve(id string, now time.Time) (Challenge, error) {
+	if id == "" {
+		id = c.defaultID
+	}
+	ch, ok := c.byID[id]
+	if !ok {
+		return Challenge{}, fmt.Errorf("%w: %q", ErrUnknownChallenge, id)
+	}
+	ch = c.at(ch, now)
+	if !ch.Active {
+		return ch, fmt.Errorf("%w: %q", ErrInactiveChallenge, id)
+	}
+	return ch, nil
+}
+
+// Default - 기본 도전
+func (c *Catalog) Default() Challenge {
+	return c.byID[c.defaultID]
+}
+
+// List - now 시점의 활성 여부를 채운 목록 (설정 순서대로)
+func (c *Catalog) List(now time.Time) []Challenge {
+	out := make([]Challenge, len(c.list))
+	for i, ch := range c.list {
+		out[i] = c.at(ch, now)
+	}
+	return out
+}
+
+// at - now 시점의 활성/오늘의 도전 여부와 보너스를 채웁니다.
+func (c *Catalog) at(ch Challenge, now time.Time) Challenge {
+	if c.rotation == nil {
+		ch.Active = true
+		return ch
+	}
+	ch.Daily = c.rotation.today(now) == ch.ID
+	ch.Active = ch.Daily || c.rotation.always[ch.ID]
+	if ch.Daily && c.rotation.bonus > 1 {
+		ch.Bonus = c.rotation.bonus
+		ch.Tier.MinAmount = int(float64(ch.Tier.MinAmount) * ch.Bonus)
+		ch.Tier.MaxAmount = int(float64(ch.Tier.MaxAmount) * ch.Bonus)
+	}
+	return ch
+}
@@ -0,0 +1,101 @@
+package challenge
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+)
+
+const dateLayout = "2006-01-02"
+
+// rotation - 날짜만으로 오늘의 도전을 정합니다. 서버가 여러 대여도, 재시작해도 같은 날에는 같은 도전입니다.
+type rotation struct {
+	ids    []string
+	always map[string]bool
+	loc    *time.Location
+	epoch  time.Time // UTC 자정 (날짜 계산용)
+	bonus  float64
+}
+
+func newRotation(cfg config.ChallengeConfig, byID map[string]Challenge) (*rotation, error) {
+	if len(cfg.Rotation) == 0 {
+		return nil, nil
+	}
+
+	r := &rotation{always: map[string]bool{}, loc: time.Local, bonus: cfg.DailyBonus}
+	for _, id := range cfg.Rotation {
+		if _, ok := byID[id]; !ok {
+			return nil, fmt.Errorf("challenge: rotation uses unknown id %q", id)
+		}
+		r.ids = append(r.ids, id)
+	}
+	for _, id := range cfg.AlwaysActive {
+		if _, ok := byID[id]; !ok {
+			return nil, fmt.Errorf("challenge: always_active uses unknown id %q", id)
+		}
+		r.always[id] = true
+	}
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("challenge: invalid timezone %q: %w", cfg.Timezone, err)
+		}
+		r.loc = loc
+	}
+	if cfg.Epoch != "" {
+		epoch, err := time.Parse(dateLayout, cfg.Epoch)
+		if err != nil {
+			return nil, fmt.Errorf("challenge: invalid epoch %q: %w", cfg.Epoch, err)
+		}
+		r.epoch = epoch
+	} else {
+		r.epoch = time.Unix(0, 0).UTC()
+	}
+	if r.bonus < 0 {
+		return nil, fmt.Errorf("challenge: daily_bonus must not be negative")
+	}
+	return r, nil
+}
+
+// day - now가 속한 (설정 시간대 기준) 날짜의 UTC 자정. 서머타임이 있어도 하루가 정확히 24시간이 되도록 UTC로 셉니다.
+func (r *rotation) day(now time.Time) time.Time {
+	y, m, d := now.In(r.loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func (r *rotation) today(now time.Time) string {
+	days := int(r.day(now).Sub(r.epoch).Hours() / 24)
+	n := len(r.ids)
+	return r.ids[((days%n)+n)%n]
+}
+
+// Daily - 오늘의 도전. 순환이 없으면 ok=false
+type Daily struct {
+	Date      string    `json:"date"` // 설정 시간대 기준 YYYY-MM-DD
+	Timezone  string    `json:"timezone"`
+	Challenge Challenge `json:"challenge"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Next      string    `json:"next"` // 내일의 도전 ID
+}
+
+// Today - now 시점의 오늘의 도전
+func (c *Catalog) Today(now time.Time) (Daily, bool) {
+	r := c.rotation
+	if r == nil {
+		return Daily{}, false
+	}
+	day := r.day(now)
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, r.loc)
+	end := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, r.loc)
+
+	return Daily{
+		Date:      day.Format(dateLayout),
+		Timezone:  r.loc.String(),
+		Challenge: c.at(c.byID[r.today(now)], now),
+		StartsAt:  start,
+		EndsAt:    end,
+		Next:      r.today(end),
+	}, true
+}
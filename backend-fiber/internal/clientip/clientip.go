@@ -0,0 +1,165 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/config"
+)
+
+const headerXRealIP = "X-Real-IP"
+
+// LocalsKey - 미들웨어가 해석한 클라이언트 IP를 저장하는 fiber Locals 키
+const LocalsKey = "clientIP"
+
+// Resolver - 신뢰하는 프록시(ingress 등) 목록을 기준으로 실제 클라이언트 IP를 찾아냅니다.
+// 신뢰하지 않는 곳에서 온 요청은 헤더를 무시하고 접속 주소를 그대로 씁니다.
+type Resolver struct {
+	trusted []*net.IPNet
+	header  string
+}
+
+func NewResolver(cfg config.ProxyConfig) (*Resolver, error) {
+	r := &Resolver{header: fiber.HeaderXForwardedFor}
+	switch strings.ToLower(strings.TrimSpace(cfg.Header)) {
+	case "", "x-forwarded-for":
+	case "forwarded":
+		r.header = fiber.HeaderForwarded
+	case "x-real-ip":
+		r.header = headerXRealIP
+	default:
+		return nil, fmt.Errorf("proxy: unsupported header %q", cfg.Header)
+	}
+	for _, raw := range cfg.TrustedCIDRs {
+		raw = strings.TrimSpace(raw)
+		if !strings.Contains(raw, "/") {
+			if ip := net.ParseIP(raw); ip != nil && ip.To4() != nil {
+				raw += "/32"
+			} else {
+				raw += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(raw)
+		if err != nil {
+			return nil, fmt.Errorf("proxy: invalid trusted cidr %q: %w", raw, err)
+		}
+		r.trusted = append(r.trusted, network)
+	}
+	return r, nil
+}
+
+// Middleware - 요청마다 클라이언트 IP를 한 번만 계산해서 Locals에 넣어둡니다.
+func (r *Resolver) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(LocalsKey, r.Resolve(c))
+		return c.Next()
+	}
+}
+
+// Resolve - 설정된 헤더 하나만 확인합니다. 프록시가 건드리지 않는 다른 헤더는 클라이언트가 꾸밀 수 있으므로 보지 않습니다.
+// 체인은 오른쪽(가장 가까운 프록시)부터 거슬러 올라가며 처음 나오는 신뢰하지 않는 주소를 클라이언트로 봅니다.
+func (r *Resolver) Resolve(c *fiber.Ctx) string {
+	remote := c.Context().RemoteIP().String()
+	if !r.isTrusted(remote) {
+		return remote
+	}
+
+	switch r.header {
+	case fiber.HeaderForwarded:
+		if chain := parseForwarded(c.Get(fiber.HeaderForwarded)); len(chain) > 0 {
+			return r.walk(chain, remote)
+		}
+	case headerXRealIP:
+		if ip := normalize(c.Get(headerXRealIP)); ip != "" {
+			return ip
+		}
+	default:
+		if chain := splitList(c.Get(fiber.HeaderXForwardedFor)); len(chain) > 0 {
+			return r.walk(chain, remote)
+		}
+	}
+	return remote
+}
+
+func (r *Resolver) walk(chain []string, remote string) string {
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := normalize(chain[i])
+		if ip == "" {
+			// 형식이 깨진 항목 뒤로는 믿을 수 없으므로 거기서 멈춥니다.
+			break
+		}
+		if !r.isTrusted(ip) {
+			return ip
+		}
+		remote = ip
+	}
+	// 전부 신뢰하는 프록시라면 가장 바깥쪽 주소를 씁니다.
+	return remote
+}
+
+func (r *Resolver) isTrusted(raw string) bool {
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return false
+	}
+	for _, network := range r.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromCtx - 미들웨어가 저장한 IP를 꺼냅니다. 미들웨어가 없으면 접속 주소를 씁니다.
+func FromCtx(c *fiber.Ctx) string {
+	if ip, ok := c.Locals(LocalsKey).(string); ok && ip != "" {
+		return ip
+	}
+	return c.Context().RemoteIP().String()
+}
+
+func splitList(header string) []string {
+	if header == "" {
+		return nil
+	}
+	parts := strings.Split(header, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// parseForwarded - RFC 7239 Forwarded 헤더에서 for= 값만 순서대로 뽑습니다.
+func parseForwarded(header string) []string {
+	var chain []string
+	for _, element := range splitList(header) {
+		for _, pair := range strings.Split(element, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || !strings.EqualFold(key, "for") {
+				continue
+			}
+			chain = append(chain, strings.Trim(value, `"`))
+		}
+	}
+	return chain
+}
+
+// normalize - 포트, IPv6 대괄호를 떼고 유효한 IP만 돌려줍니다.
+func normalize(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(raw); err == nil {
+		raw = host
+	}
+	raw = strings.TrimSuffix(strings.TrimPrefix(raw, "["), "]")
+
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
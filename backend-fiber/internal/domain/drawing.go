@@ -2,12 +2,60 @@ package domain
 
 type DrawingRequest struct {
 	ImageData string `json:"image"`
+
+	// 리더보드에 올릴 플레이어 (둘 다 없으면 순위에 넣지 않습니다)
+	Wallet   string `json:"wallet,omitempty"`
+	Nickname string `json:"nickname,omitempty"`
+
+	// 그릴 도형 ID (비우면 기본 도전)
+	Challenge string `json:"challenge,omitempty"`
+
+	// 게이트웨이가 채우는 값 (요청 본문으로는 받지 않음)
+	ImageHash string  `json:"-"` // 정규화된 그림의 SHA-256 (그림 보관소 키)
+	PHash     string  `json:"-"` // 지각 해시 "aHash:dHash" (유사 그림 판별용)
+	Symbol    string  `json:"-"` // 목표 기호 (예: "?")
+	Prompt    string  `json:"-"` // 분석기에 넘기는 목표 설명
+	InkExtent float64 `json:"-"` // 정규화 전 잉크 영역 크기 (캔버스 짧은 변 대비, 0이면 모름)
 }
 type AnalysisResult struct {
 	Similarity float64 `json:"similarity"`
 	Feedback   string  `json:"feedback"`
 	FeedbackKo string  `json:"feedback_ko"`
 	TxId       string  `json:"tx_id"`
+	Challenge  string  `json:"challenge,omitempty"`
+	Daily      bool    `json:"daily,omitempty"` // 오늘의 도전 (보너스 보상)
+
+	// 점수를 낸 분석기. 주 분석기가 실패해 대체 분석기가 점수를 낸 경우 Fallback이 true
+	Analyzer       string `json:"analyzer,omitempty"`
+	Fallback       bool   `json:"fallback,omitempty"`
+	FallbackReason string `json:"fallback_reason,omitempty"`
+
+	// A/B 실험군과 앙상블 구성 (분석기별 점수)
+	Variant   string             `json:"variant,omitempty"`
+	Analyzers []string           `json:"analyzers,omitempty"`
+	Scores    map[string]float64 `json:"scores,omitempty"`
+	// 앙상블에서 실패한 분석기. 있으면 남은 분석기로만 낸 점수라 Fallback으로 취급합니다.
+	FailedAnalyzers []string `json:"failed_analyzers,omitempty"`
+
+	// 같은 그림의 이전 분석 결과를 재사용한 경우
+	Cached bool `json:"cached,omitempty"`
+
+	// 보상 정책 판정 (allow | downgrade | block | rate_limited | pow_required). 유사 그림에 걸리면 원본 traceID
+	RewardDecision string `json:"reward_decision,omitempty"`
+	DuplicateOf    string `json:"duplicate_of,omitempty"`
+	// 보상 자격 제한에 걸렸을 때 다시 보상받을 수 있을 때까지 (초)
+	RewardRetryAfter int `json:"reward_retry_after,omitempty"`
+}
+
+// RewardRequest - 보상 도구(polygon)에 넘기는 값. Multiplier가 1보다 작으면 지급량을 줄입니다.
+type RewardRequest struct {
+	Result     AnalysisResult
+	Recipient  string // 로그인으로 확인된 지갑 주소 (없으면 도구 기본 주소)
+	Multiplier float64
+
+	// 도전의 보상 등급 범위 (0이면 도구 기본값)
+	MinAmount int
+	MaxAmount int
 }
 
 type SpringAIResponse struct {
@@ -17,17 +65,28 @@ type SpringAIResponse struct {
 
 type AnalysisHistory struct {
 	// 1. 기본 접속 정보
-	Timestamp string `json:"timestamp"`
-	TraceID   string `json:"traceID"`
-	IP        string `json:"ip"`
-	UserAgent string `json:"userAgent"`
-	Referer   string `json:"referer"`
-	Path      string `json:"path"`
+	Timestamp      string `json:"timestamp"`
+	TraceID        string `json:"traceID"`
+	ImageHash      string `json:"imageHash,omitempty"`
+	PHash          string `json:"pHash,omitempty"`
+	IP             string `json:"ip"`
+	UserAgent      string `json:"userAgent"`
+	Referer        string `json:"referer"`
+	Path           string `json:"path"`
+	Wallet         string `json:"wallet,omitempty"`
+	WalletVerified bool   `json:"walletVerified,omitempty"` // SIWE 로그인으로 확인된 주소인지
+	Nickname       string `json:"nickname,omitempty"`
+	Challenge      string `json:"challenge,omitempty"`
+	Daily          bool   `json:"daily,omitempty"`
 
 	// 2. 환경 분석 정보 (OS, 브라우저 등)
-	Device  string `json:"device"`
-	Browser string `json:"browser"`
-	OS      string `json:"os"`
+	Device         string `json:"device"`
+	Browser        string `json:"browser"`
+	BrowserVersion string `json:"browserVersion,omitempty"`
+	OS             string `json:"os"`
+	OSVersion      string `json:"osVersion,omitempty"`
+	IsBot          bool   `json:"isBot,omitempty"`
+	BotName        string `json:"botName,omitempty"`
 
 	// 3. AI 분석 결과
 	Similarity float64 `json:"similarity"`
@@ -36,4 +95,36 @@ type AnalysisHistory struct {
 	TxId       string  `json:"txId"`
 	Status     int     `json:"status"`
 	Error      string  `json:"error,omitempty"`
+
+	Analyzer       string `json:"analyzer,omitempty"`
+	Fallback       bool   `json:"fallback,omitempty"`
+	FallbackReason string `json:"fallbackReason,omitempty"`
+
+	Variant         string             `json:"variant,omitempty"`
+	Analyzers       []string           `json:"analyzers,omitempty"`
+	AnalyzerScores  map[string]float64 `json:"analyzerScores,omitempty"`
+	FailedAnalyzers []string           `json:"failedAnalyzers,omitempty"`
+	Cached          bool               `json:"cached,omitempty"`
+	RewardDecision  string             `json:"rewardDecision,omitempty"`
+	DuplicateOf     string             `json:"duplicateOf,omitempty"`
+
+	// 4. 무결성 체인 (이전 레코드 해시로 연결)
+	PrevHash    string `json:"prevHash,omitempty"`
+	ContentHash string `json:"contentHash,omitempty"`
+	Hash        string `json:"hash,omitempty"`
+	Redacted    bool   `json:"redacted,omitempty"`
+	// 삭제 표시 서명 (서명 키 없이는 레코드를 지운 것처럼 꾸밀 수 없도록)
+	RedactionSig string `json:"redactionSig,omitempty"`
+}
+
+type ErasureRequest struct {
+	TraceID string `json:"trace_id"`
+	IPHash  string `json:"ip_hash"`
+	IP      string `json:"ip"`
+}
+
+type ErasureReport struct {
+	TraceIDs       []string `json:"trace_ids"`
+	HistoryRemoved int      `json:"history_removed"`
+	ImagesRemoved  int      `json:"images_removed"`
 }
@@ -0,0 +1,211 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+)
+
+// 분석/보상 진행 단계
+const (
+	StageReceived     = "received"
+	StageValidated    = "validated"
+	StageAnalyzing    = "analyzing"
+	StageScored       = "scored"
+	StageRewardQueued = "reward_queued"
+	StageTxSent       = "tx_sent"
+	StageTxConfirmed  = "tx_confirmed"
+	StageFailed       = "failed"
+	StageDone         = "done"
+)
+
+const (
+	defaultRetention      = 10 * time.Minute
+	defaultMaxConnections = 500
+	defaultMaxPerIP       = 5
+	maxEvents             = 64 // traceID 하나당 보관하는 이벤트 수
+	subscriberBuffer      = 16
+)
+
+var (
+	// ErrTooManyConnections - 전체 구독 수 한도 초과 (503)
+	ErrTooManyConnections = errors.New("event streams are full")
+	// ErrTooManyFromIP - 같은 IP의 구독 수 한도 초과 (429)
+	ErrTooManyFromIP = errors.New("too many event streams from this address")
+)
+
+// Event - traceID 하나의 진행 상황. Final이면 더 이상 이벤트가 오지 않습니다.
+type Event struct {
+	TraceID string         `json:"trace_id"`
+	Stage   string         `json:"stage"`
+	Time    time.Time      `json:"time"`
+	Data    map[string]any `json:"data,omitempty"`
+	Final   bool           `json:"final,omitempty"`
+}
+
+type stream struct {
+	events  []Event
+	subs    map[chan Event]struct{}
+	updated time.Time
+}
+
+// Bus - traceID별로 이벤트를 모아두고 구독자에게 전달합니다.
+// 늦게 연결한 클라이언트도 처음부터 볼 수 있도록 최근 이벤트를 Retention 동안 보관합니다.
+type Bus struct {
+	cfg       config.EventsConfig
+	retention time.Duration
+
+	mu      sync.Mutex
+	streams map[string]*stream
+	subs    int
+	perIP   map[string]int
+}
+
+func NewBus(cfg config.EventsConfig) *Bus {
+	retention := time.Duration(cfg.RetentionMinutes) * time.Minute
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	if cfg.MaxConnections <= 0 {
+		cfg.MaxConnections = defaultMaxConnections
+	}
+	if cfg.MaxPerIP <= 0 {
+		cfg.MaxPerIP = defaultMaxPerIP
+	}
+	return &Bus{cfg: cfg, retention: retention, streams: map[string]*stream{}, perIP: map[string]int{}}
+}
+
+// Start - 오래된 스트림 정리 루프
+func (b *Bus) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				b.sweep()
+			}
+		}
+	}()
+}
+
+func (b *Bus) Publish(e Event) {
+	if e.TraceID == "" {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.streams[e.TraceID]
+	if s == nil {
+		s = &stream{subs: map[chan Event]struct{}{}}
+		b.streams[e.TraceID] = s
+	}
+	s.updated = e.Time
+	if len(s.events) < maxEvents {
+		s.events = append(s.events, e)
+	}
+	for ch := range s.subs {
+		// 느린 구독자 때문에 발행하는 쪽(분석 경로)이 멈추지 않도록 넘치면 버립니다.
+		select {
+		case ch <- e:
+			continue
+		default:
+		}
+		if !e.Final {
+			continue
+		}
+		// 마지막 이벤트를 못 받으면 구독자가 끝을 모르고 기다리므로, 대기 중인 이벤트 하나를 버리고 자리를 만듭니다.
+		// 보내는 쪽은 잠금을 잡은 Publish뿐이라 비운 자리는 다른 이벤트가 채우지 못합니다.
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe - 지금까지의 이벤트와 이후 이벤트를 받을 채널. 다 쓰면 cancel을 불러야 합니다.
+// 아무 traceID로나 연결을 오래 붙잡지 못하도록 전체/IP별 구독 수를 제한합니다.
+func (b *Bus) Subscribe(traceID, ip string) ([]Event, <-chan Event, func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs >= b.cfg.MaxConnections {
+		return nil, nil, nil, ErrTooManyConnections
+	}
+	if b.perIP[ip] >= b.cfg.MaxPerIP {
+		return nil, nil, nil, ErrTooManyFromIP
+	}
+
+	s := b.streams[traceID]
+	if s == nil {
+		s = &stream{subs: map[chan Event]struct{}{}, updated: time.Now()}
+		b.streams[traceID] = s
+	}
+	replay := append([]Event(nil), s.events...)
+	ch := make(chan Event, subscriberBuffer)
+	s.subs[ch] = struct{}{}
+	b.subs++
+	b.perIP[ip]++
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(s.subs, ch)
+			b.subs--
+			if b.perIP[ip]--; b.perIP[ip] <= 0 {
+				delete(b.perIP, ip)
+			}
+		})
+	}
+	return replay, ch, cancel, nil
+}
+
+func (b *Bus) sweep() {
+	cutoff := time.Now().Add(-b.retention)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id, s := range b.streams {
+		if len(s.subs) == 0 && s.updated.Before(cutoff) {
+			delete(b.streams, id)
+		}
+	}
+}
+
+type emitterKey struct{}
+
+// Emitter - context에 실어 보내는 이벤트 발행 함수 (traceID가 이미 묶여 있음)
+type Emitter func(stage string, data map[string]any, final bool)
+
+// WithEmitter - 하위 도구(Provider)가 Emit으로 진행 상황을 알릴 수 있도록 context에 싣습니다.
+func WithEmitter(ctx context.Context, emit Emitter) context.Context {
+	return context.WithValue(ctx, emitterKey{}, emit)
+}
+
+// FromContext - context에 실린 Emitter. 없으면 아무것도 하지 않는 함수를 돌려줍니다.
+func FromContext(ctx context.Context) Emitter {
+	if emit, ok := ctx.Value(emitterKey{}).(Emitter); ok {
+		return emit
+	}
+	return func(string, map[string]any, bool) {}
+}
+
+// Emit - FromContext(ctx)(...)의 줄임
+func Emit(ctx context.Context, stage string, data map[string]any, final bool) {
+	FromContext(ctx)(stage, data, final)
+}
@@ -0,0 +1,305 @@
+package history
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+const checkpointPrefix = "checkpoint-"
+
+// 해시 계산에서 제외하는 체인 필드들
+var chainFields = []string{"prevHash", "contentHash", "hash", "redacted", "redactionSig"}
+
+// Chain - 히스토리 레코드를 앞 레코드의 해시로 엮고, 날짜별 체크포인트에 서명합니다.
+type Chain struct {
+	key []byte
+}
+
+func NewChain(signingKey string) (*Chain, error) {
+	if signingKey == "" {
+		return nil, fmt.Errorf("integrity: signing_key is required")
+	}
+	if config.IsPlaceholder(signingKey) {
+		return nil, fmt.Errorf("integrity: signing_key is a placeholder value, set a random secret")
+	}
+	return &Chain{key: []byte(signingKey)}, nil
+}
+
+// Checkpoint - 하루치 체인의 마지막 상태와 그 서명
+type Checkpoint struct {
+	Date      string `json:"date"`
+	Count     int    `json:"count"`
+	HeadHash  string `json:"headHash"`
+	Signature string `json:"signature"`
+}
+
+// Seal - 레코드에 prevHash, contentHash, hash를 채웁니다.
+func (c *Chain) Seal(record *domain.AnalysisHistory, prevHash string) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	contentHash, err := contentDigest(raw)
+	if err != nil {
+		return err
+	}
+
+	record.PrevHash = prevHash
+	record.ContentHash = contentHash
+	record.Hash = linkHash(prevHash, contentHash)
+	return nil
+}
+
+// Redact - 개인정보 삭제 시 체인은 유지하고 내용만 비운 레코드를 만듭니다.
+// 내용을 더 이상 해시로 확인할 수 없으므로 삭제 표시에 서명해 둡니다.
+func (c *Chain) Redact(record domain.AnalysisHistory) domain.AnalysisHistory {
+	return domain.AnalysisHistory{
+		Timestamp:    record.Timestamp,
+		PrevHash:     record.PrevHash,
+		ContentHash:  record.ContentHash,
+		Hash:         record.Hash,
+		Redacted:     true,
+		RedactionSig: c.signRedaction(record.Hash),
+	}
+}
+
+// WriteCheckpoint - 해당 날짜의 체크포인트 파일을 갱신합니다.
+func (c *Chain) WriteCheckpoint(dir string, day time.Time, count int, headHash string) error {
+	cp := Checkpoint{
+		Date:     day.Format(dateLayout),
+		Count:    count,
+		HeadHash: headHash,
+	}
+	cp.Signature = c.sign(cp)
+
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := checkpointPath(dir, day)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (c *Chain) signRedaction(hash string) string {
+	mac := hmac.New(sha256.New, c.key)
+	fmt.Fprintf(mac, "redacted|%s", hash)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Chain) sign(cp Checkpoint) string {
+	mac := hmac.New(sha256.New, c.key)
+	fmt.Fprintf(mac, "%s|%d|%s", cp.Date, cp.Count, cp.HeadHash)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyReport - 체인 검증 결과. Broken이 비어 있으면 정상입니다.
+type VerifyReport struct {
+	Files       int    `json:"files"`
+	Records     int    `json:"records"`
+	Checkpoints int    `json:"checkpoints"`
+	Legacy      int    `json:"legacy"` // 체인 도입 이전에 저장된 레코드 수
+	Anchor      string `json:"anchor"` // 남아있는 가장 오래된 레코드의 prevHash (보관 정책으로 앞부분이 지워졌을 수 있음)
+	Broken      string `json:"broken,omitempty"`
+}
+
+func (r *VerifyReport) OK() bool {
+	return r.Broken == ""
+}
+
+// Verify - 여러 디렉토리(현재 + 보관)의 히스토리를 날짜순으로 이어서 검증하고, 처음 끊어진 지점을 보고합니다.
+func (c *Chain) Verify(dirs ...string) (*VerifyReport, error) {
+	type dayFile struct {
+		day  time.Time
+		path string
+	}
+
+	var files []dayFile
+	for _, dir := range dirs {
+		paths, err := Files(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			day, _, _ := ParseFileName(filepath.Base(path))
+			files = append(files, dayFile{day: day, path: path})
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].day.Before(files[j].day) })
+
+	report := &VerifyReport{}
+	seen := map[string]bool{}
+	started := false
+	prev := ""
+	for _, f := range files {
+		records, err := loadRaw(f.path)
+		if err != nil {
+			// 읽지 못하는 파일도 변조로 봅니다. (잘리거나 덮어쓴 파일)
+			report.Broken = fmt.Sprintf("%s: file cannot be decoded: %v", filepath.Base(f.path), err)
+			return report, nil
+		}
+		report.Files++
+		seen[f.day.Format(dateLayout)] = true
+
+		sealed := 0
+		for i, raw := range records {
+			var rec domain.AnalysisHistory
+			if err := json.Unmarshal(raw, &rec); err != nil {
+				report.Broken = fmt.Sprintf("%s #%d: record cannot be decoded: %v", filepath.Base(f.path), i, err)
+				return report, nil
+			}
+			where := fmt.Sprintf("%s #%d (traceID=%s)", filepath.Base(f.path), i, rec.TraceID)
+
+			if rec.Hash == "" {
+				if !started {
+					report.Legacy++
+					continue
+				}
+				report.Broken = where + ": record is not sealed"
+				return report, nil
+			}
+			if !started {
+				started = true
+				report.Anchor = rec.PrevHash
+				prev = rec.PrevHash
+			}
+			if rec.PrevHash != prev {
+				report.Broken = where + ": prevHash does not match previous record"
+				return report, nil
+			}
+			if rec.Redacted {
+				if !hmac.Equal([]byte(rec.RedactionSig), []byte(c.signRedaction(rec.Hash))) {
+					report.Broken = where + ": redaction is not signed"
+					return report, nil
+				}
+			} else {
+				digest, err := contentDigest(raw)
+				if err != nil {
+					return nil, err
+				}
+				if digest != rec.ContentHash {
+					report.Broken = where + ": content was modified"
+					return report, nil
+				}
+			}
+			if linkHash(rec.PrevHash, rec.ContentHash) != rec.Hash {
+				report.Broken = where + ": hash mismatch"
+				return report, nil
+			}
+
+			prev = rec.Hash
+			sealed++
+			report.Records++
+		}
+
+		ok, found, err := c.verifyCheckpoint(filepath.Dir(f.path), f.day, sealed, prev)
+		if err != nil {
+			return nil, err
+		}
+		// 봉인된 레코드가 있는 날은 체크포인트가 반드시 있어야 합니다. (지우면 체인을 다시 계산해 꾸밀 수 있으므로)
+		if !found && sealed > 0 {
+			report.Broken = filepath.Base(f.path) + ": checkpoint is missing"
+			return report, nil
+		}
+		if found {
+			report.Checkpoints++
+		}
+		if found && !ok {
+			report.Broken = filepath.Base(f.path) + ": checkpoint does not match (signature, count or head hash)"
+			return report, nil
+		}
+	}
+
+	// 체크포인트는 있는데 히스토리 파일이 통째로 사라진 경우
+	for _, dir := range dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, checkpointPrefix+"*"+fileExt))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			date := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(m), checkpointPrefix), fileExt)
+			if !seen[date] {
+				report.Broken = filepath.Base(m) + ": history file for this checkpoint is missing"
+				return report, nil
+			}
+		}
+	}
+	return report, nil
+}
+
+// verifyCheckpoint - 체크포인트가 없으면 found=false. (보관 디렉토리로 옮길 때 체크포인트도 함께 옮깁니다)
+func (c *Chain) verifyCheckpoint(dir string, day time.Time, count int, headHash string) (bool, bool, error) {
+	data, err := os.ReadFile(checkpointPath(dir, day))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return false, true, nil
+	}
+	ok := hmac.Equal([]byte(cp.Signature), []byte(c.sign(cp))) && cp.Count == count && cp.HeadHash == headHash
+	return ok, true, nil
+}
+
+// contentDigest - 체인 필드를 뺀 나머지 내용을 키 정렬된 JSON으로 만들어 해시합니다.
+// 구조체 대신 map으로 정규화하므로 나중에 필드가 추가되어도 예전 레코드 검증에 영향이 없습니다.
+func contentDigest(raw []byte) (string, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", err
+	}
+	for _, k := range chainFields {
+		delete(fields, k)
+	}
+	canonical, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func checkpointPath(dir string, day time.Time) string {
+	return filepath.Join(dir, checkpointPrefix+day.Format(dateLayout)+fileExt)
+}
+
+func linkHash(prevHash, contentHash string) string {
+	sum := sha256.Sum256([]byte(prevHash + contentHash))
+	return hex.EncodeToString(sum[:])
+}
+
+func loadRaw(path string) ([]json.RawMessage, error) {
+	r, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var records []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+	return records, nil
+}
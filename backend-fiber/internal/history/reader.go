@@ -0,0 +1,127 @@
+package history
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+// DefaultDir - 히스토리 파일 기본 경로 (k8s hostPath 볼륨)
+const DefaultDir = "/mnt/visit-logs"
+
+const (
+	filePrefix = "history-"
+	fileExt    = ".json"
+	gzipExt    = ".gz"
+	dateLayout = "2006-01-02"
+)
+
+// FileName - 날짜별 히스토리 파일 이름 (history-2006-01-02.json)
+func FileName(day time.Time) string {
+	return filePrefix + day.Format(dateLayout) + fileExt
+}
+
+// ParseFileName - 파일 이름에서 날짜와 압축 여부를 추출합니다.
+func ParseFileName(name string) (time.Time, bool, bool) {
+	compressed := strings.HasSuffix(name, gzipExt)
+	base := strings.TrimSuffix(name, gzipExt)
+	if !strings.HasPrefix(base, filePrefix) || !strings.HasSuffix(base, fileExt) {
+		return time.Time{}, false, false
+	}
+
+	raw := strings.TrimSuffix(strings.TrimPrefix(base, filePrefix), fileExt)
+	day, err := time.ParseInLocation(dateLayout, raw, time.Local)
+	if err != nil {
+		return time.Time{}, false, false
+	}
+	return day, compressed, true
+}
+
+// Open - 평문(.json)과 압축(.json.gz) 파일을 구분하지 않고 읽을 수 있게 열어줍니다.
+func Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, gzipExt) {
+		return f, nil
+	}
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("gzip reader: %w", err)
+	}
+	return &gzipReadCloser{Reader: zr, file: f}, nil
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipReadCloser) Close() error {
+	zerr := g.Reader.Close()
+	ferr := g.file.Close()
+	if zerr != nil {
+		return zerr
+	}
+	return ferr
+}
+
+// LoadFile - 히스토리 파일 하나를 읽어 레코드 배열로 돌려줍니다.
+func LoadFile(path string) ([]domain.AnalysisHistory, error) {
+	r, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var histories []domain.AnalysisHistory
+	if err := json.NewDecoder(r).Decode(&histories); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+	return histories, nil
+}
+
+// LoadDay - 해당 날짜의 파일을 평문 → 압축 순서로 찾아 읽습니다. 파일이 없으면 빈 배열입니다.
+func LoadDay(dir string, day time.Time) ([]domain.AnalysisHistory, error) {
+	plain := filepath.Join(dir, FileName(day))
+	for _, path := range []string{plain, plain + gzipExt} {
+		histories, err := LoadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		return histories, err
+	}
+	return nil, nil
+}
+
+// Files - 디렉토리 안의 히스토리 파일 경로를 날짜순으로 돌려줍니다.
+func Files(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if _, _, ok := ParseFileName(e.Name()); ok {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
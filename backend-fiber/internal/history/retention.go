@@ -0,0 +1,230 @@
+package history
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+)
+
+// RetentionReport - 한 번의 정리 작업 결과
+type RetentionReport struct {
+	Compressed []string
+	Archived   []string
+	Deleted    []string
+	Failed     []string
+}
+
+// RetentionManager - 오래된 히스토리 파일을 압축하고, 더 오래된 파일은 보관 디렉토리로 옮기거나 삭제합니다.
+type RetentionManager struct {
+	cfg    config.RetentionConfig
+	store  *Store // 파일을 바꾸는 동안 Store의 쓰기/삭제를 막습니다
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+func NewRetentionManager(cfg config.RetentionConfig, store *Store, logger *slog.Logger) *RetentionManager {
+	if cfg.Dir == "" {
+		cfg.Dir = store.Dir()
+	}
+	if cfg.IntervalMinutes <= 0 {
+		cfg.IntervalMinutes = 60
+	}
+	return &RetentionManager{
+		cfg:    cfg,
+		store:  store,
+		logger: logger.With("layer", "retention"),
+		now:    time.Now,
+	}
+}
+
+// Start - 설정된 주기마다 Run을 실행합니다. 시작 직후 한 번 바로 실행합니다.
+func (m *RetentionManager) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(m.cfg.IntervalMinutes) * time.Minute)
+		defer ticker.Stop()
+
+		for {
+			if _, err := m.Run(); err != nil {
+				m.logger.Error("히스토리 정리 실패", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run - 정리 작업을 한 번 수행합니다. 오늘 파일은 아직 쓰는 중이므로 건드리지 않습니다.
+func (m *RetentionManager) Run() (RetentionReport, error) {
+	var report RetentionReport
+
+	paths, err := Files(m.cfg.Dir)
+	if err != nil {
+		return report, fmt.Errorf("list history files: %w", err)
+	}
+
+	today := truncateDay(m.now())
+	for _, path := range paths {
+		day, compressed, _ := ParseFileName(filepath.Base(path))
+		age := int(today.Sub(day).Hours() / 24)
+		if age < 1 {
+			continue
+		}
+
+		// 삭제 요청(Erase)이 같은 파일을 다시 쓰는 중에 압축/이동하지 않도록 Store와 같은 잠금을 씁니다.
+		m.store.mu.Lock()
+		m.apply(path, day, compressed, age, &report)
+		m.store.mu.Unlock()
+	}
+
+	if len(report.Compressed)+len(report.Archived)+len(report.Deleted)+len(report.Failed) > 0 {
+		m.logger.Info("히스토리 정리 완료",
+			"compressed", len(report.Compressed),
+			"archived", len(report.Archived),
+			"deleted", len(report.Deleted),
+			"failed", len(report.Failed),
+		)
+	}
+	return report, nil
+}
+
+// apply - 파일 하나에 정책(보관/삭제/압축)을 적용합니다. Store 잠금을 잡은 채로 호출합니다.
+func (m *RetentionManager) apply(path string, day time.Time, compressed bool, age int, report *RetentionReport) {
+	switch {
+	case m.cfg.DeleteAfterDays > 0 && age >= m.cfg.DeleteAfterDays:
+		if m.cfg.ArchiveDir != "" {
+			if err := m.archive(path, day); err != nil {
+				m.logger.Error("히스토리 보관 실패", "file", path, "error", err)
+				report.Failed = append(report.Failed, path)
+				return
+			}
+			report.Archived = append(report.Archived, path)
+			return
+		}
+		if err := os.Remove(path); err != nil {
+			m.logger.Error("히스토리 삭제 실패", "file", path, "error", err)
+			report.Failed = append(report.Failed, path)
+			return
+		}
+		// 체인 체크포인트도 같이 정리합니다.
+		if err := os.Remove(checkpointPath(m.cfg.Dir, day)); err != nil && !os.IsNotExist(err) {
+			m.logger.Error("체크포인트 삭제 실패", "file", path, "error", err)
+		}
+		report.Deleted = append(report.Deleted, path)
+
+	case !compressed && m.cfg.CompressAfterDays > 0 && age >= m.cfg.CompressAfterDays:
+		if err := compressFile(path); err != nil {
+			m.logger.Error("히스토리 압축 실패", "file", path, "error", err)
+			report.Failed = append(report.Failed, path)
+			return
+		}
+		report.Compressed = append(report.Compressed, path)
+	}
+}
+
+// archive - 보관 디렉토리로 옮깁니다. 압축 안 된 파일은 압축해서 옮기고, 체크포인트도 함께 옮깁니다.
+func (m *RetentionManager) archive(path string, day time.Time) error {
+	if err := os.MkdirAll(m.cfg.ArchiveDir, 0755); err != nil {
+		return err
+	}
+
+	if _, compressed, _ := ParseFileName(filepath.Base(path)); !compressed {
+		if err := compressFile(path); err != nil {
+			return err
+		}
+		path += gzipExt
+	}
+
+	if err := moveFile(path, filepath.Join(m.cfg.ArchiveDir, filepath.Base(path))); err != nil {
+		return err
+	}
+
+	checkpoint := checkpointPath(m.cfg.Dir, day)
+	if _, err := os.Stat(checkpoint); err != nil {
+		return nil
+	}
+	return moveFile(checkpoint, checkpointPath(m.cfg.ArchiveDir, day))
+}
+
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	// 다른 볼륨이면 rename이 실패하므로 복사 후 삭제
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// compressFile - path를 path.gz로 압축하고 원본을 지웁니다. 중간에 실패하면 원본은 그대로 남습니다.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + gzipExt + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(path)
+	if _, err := io.Copy(zw, src); err != nil {
+		zw.Close()
+		dst.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, path+gzipExt); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+}
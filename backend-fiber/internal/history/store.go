@@ -0,0 +1,243 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+// Store - 날짜별 히스토리 파일에 대한 쓰기/삭제를 한 곳에서 직렬화합니다.
+type Store struct {
+	dir         string
+	archiveDirs []string
+	chain       *Chain // nil이면 해시 체인 비활성화
+	logger      *slog.Logger
+	mu          sync.Mutex // 파일 쓰기 동시성 제어
+
+	lastHash   string
+	lastLoaded bool
+}
+
+func NewStore(dir string, archiveDirs []string, chain *Chain, logger *slog.Logger) *Store {
+	// 로그 디렉토리가 없으면 미리 생성합니다.
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logger.Error("로그 디렉토리 생성 실패", "path", dir, "error", err)
+	}
+
+	return &Store{
+		dir:         dir,
+		archiveDirs: archiveDirs,
+		chain:       chain,
+		logger:      logger.With("layer", "history"),
+	}
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Append - 오늘 파일에 레코드 하나를 추가합니다. (기존 배열 추가 방식 유지)
+func (s *Store) Append(record domain.AnalysisHistory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	filename := filepath.Join(s.dir, FileName(now))
+
+	// 압축된 파일이든 평문이든 같은 방식으로 읽습니다.
+	// 읽지 못하는 파일을 새 레코드 하나로 덮어쓰면 기존 기록과 변조 흔적이 함께 사라지므로 쓰지 않고 실패합니다.
+	histories, err := LoadDay(s.dir, now)
+	if err != nil {
+		return fmt.Errorf("load today's history: %w", err)
+	}
+
+	if s.chain != nil {
+		head, err := s.headHash()
+		if err != nil {
+			return fmt.Errorf("load chain head: %w", err)
+		}
+		if err := s.chain.Seal(&record, head); err != nil {
+			return fmt.Errorf("seal record: %w", err)
+		}
+	}
+
+	histories = append(histories, record)
+	if err := writeFile(filename, histories); err != nil {
+		return err
+	}
+
+	if s.chain != nil {
+		s.lastHash = record.Hash
+		if err := s.chain.WriteCheckpoint(s.dir, now, sealedCount(histories), record.Hash); err != nil {
+			s.logger.Error("체크포인트 저장 실패", "error", err)
+		}
+	}
+	return nil
+}
+
+// headHash - 체인의 마지막 해시. 처음 호출될 때 가장 최근 파일에서 읽어옵니다.
+// 읽지 못하는 파일이 있으면 그보다 오래된 헤드에 이어 붙이지 않도록 에러를 돌려줍니다.
+func (s *Store) headHash() (string, error) {
+	if s.lastLoaded {
+		return s.lastHash, nil
+	}
+
+	var paths []string
+	for _, dir := range append(s.archiveDirs, s.dir) {
+		found, err := Files(dir)
+		if err != nil {
+			continue
+		}
+		paths = append(paths, found...)
+	}
+	sort.Slice(paths, func(i, j int) bool { return filepath.Base(paths[i]) < filepath.Base(paths[j]) })
+
+	for i := len(paths) - 1; i >= 0; i-- {
+		histories, err := LoadFile(paths[i])
+		if err != nil {
+			return "", err
+		}
+		for j := len(histories) - 1; j >= 0; j-- {
+			if histories[j].Hash != "" {
+				s.lastHash, s.lastLoaded = histories[j].Hash, true
+				return s.lastHash, nil
+			}
+		}
+	}
+	s.lastLoaded = true
+	return "", nil
+}
+
+// Verify - 현재 디렉토리와 보관 디렉토리 전체의 체인을 검증합니다.
+func (s *Store) Verify() (*VerifyReport, error) {
+	if s.chain == nil {
+		return nil, fmt.Errorf("integrity chain is disabled")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.chain.Verify(append([]string{s.dir}, s.archiveDirs...)...)
+}
+
+// Erase - match에 해당하는 레코드를 보관 디렉토리까지 포함한 모든 파일에서 지우고, 지운 레코드를 돌려줍니다.
+// 해시 체인이 켜져 있으면 레코드를 빼는 대신 내용만 비워서 체인을 유지합니다.
+func (s *Store) Erase(match func(domain.AnalysisHistory) bool) ([]domain.AnalysisHistory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []domain.AnalysisHistory
+	for _, dir := range append([]string{s.dir}, s.archiveDirs...) {
+		paths, err := Files(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return removed, err
+		}
+
+		for _, path := range paths {
+			histories, err := LoadFile(path)
+			if err != nil {
+				return removed, err
+			}
+
+			kept := histories[:0]
+			before := len(removed)
+			for _, h := range histories {
+				if h.Redacted || !match(h) {
+					kept = append(kept, h)
+					continue
+				}
+				removed = append(removed, h)
+				if s.chain != nil && h.Hash != "" {
+					kept = append(kept, s.chain.Redact(h))
+				}
+			}
+			if len(removed) == before {
+				continue
+			}
+
+			if err := rewriteFile(path, kept); err != nil {
+				return removed, fmt.Errorf("rewrite %s: %w", filepath.Base(path), err)
+			}
+		}
+	}
+	return removed, nil
+}
+
+// Scan - 보관 디렉토리까지 포함한 모든 레코드를 날짜 순으로 fn에 넘깁니다. fn이 false를 돌려주면 멈춥니다.
+func (s *Store) Scan(fn func(domain.AnalysisHistory) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, dir := range append(append([]string{}, s.archiveDirs...), s.dir) {
+		paths, err := Files(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			histories, err := LoadFile(path)
+			if err != nil {
+				return err
+			}
+			for _, h := range histories {
+				if !fn(h) {
+					return nil
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sealedCount - 체크포인트에 들어가는 레코드 수. 체인 도입 전 레코드는 세지 않습니다.
+func sealedCount(histories []domain.AnalysisHistory) int {
+	count := 0
+	for _, h := range histories {
+		if h.Hash != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// rewriteFile - 원래 형식(평문/압축)을 유지하며 파일 내용을 교체합니다.
+func rewriteFile(path string, histories []domain.AnalysisHistory) error {
+	if !strings.HasSuffix(path, gzipExt) {
+		return writeFile(path, histories)
+	}
+
+	plain := strings.TrimSuffix(path, gzipExt)
+	if err := writeFile(plain, histories); err != nil {
+		return err
+	}
+	return compressFile(plain)
+}
+
+func writeFile(path string, histories []domain.AnalysisHistory) error {
+	if histories == nil {
+		histories = []domain.AnalysisHistory{}
+	}
+
+	// 보기 편하게 Indent 적용하여 저장
+	jsonData, err := json.MarshalIndent(histories, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json marshal: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, jsonData, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
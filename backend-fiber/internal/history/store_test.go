@@ -0,0 +1,90 @@
+package history
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+const testSigningKey = "0123456789abcdef0123456789abcdef"
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	chain, err := NewChain(testSigningKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStore(t.TempDir(), nil, chain, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestAppendChainsRecords(t *testing.T) {
+	store := newTestStore(t)
+	for _, id := range []string{"a", "b", "c"} {
+		if err := store.Append(domain.AnalysisHistory{TraceID: id, Timestamp: time.Now().Format(time.RFC3339)}); err != nil {
+			t.Fatalf("append %s: %v", id, err)
+		}
+	}
+
+	report, err := store.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() || report.Records != 3 {
+		t.Fatalf("report = %+v", report)
+	}
+}
+
+// 오늘 파일이 깨져 있으면 새 레코드로 덮어쓰지 않고 실패해야 하며, 검증은 깨진 곳을 알려야 합니다.
+func TestAppendRefusesUndecodableDayFile(t *testing.T) {
+	store := newTestStore(t)
+	if err := store.Append(domain.AnalysisHistory{TraceID: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(store.Dir(), FileName(time.Now()))
+	garbage := []byte(`[{"traceID": "a", "hash": "trunc`)
+	if err := os.WriteFile(path, garbage, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Append(domain.AnalysisHistory{TraceID: "b"}); err == nil {
+		t.Fatal("append succeeded on an undecodable day file")
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(garbage) {
+		t.Fatalf("day file was rewritten: %s", got)
+	}
+
+	report, err := store.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.OK() || !strings.Contains(report.Broken, "cannot be decoded") {
+		t.Fatalf("report = %+v", report)
+	}
+}
+
+// 체인 헤드를 처음 읽을 때 최근 파일이 깨져 있으면 더 오래된 헤드에 이어 붙이지 않아야 합니다.
+func TestAppendRefusesUndecodableHead(t *testing.T) {
+	store := newTestStore(t)
+	yesterday := filepath.Join(store.Dir(), FileName(time.Now().AddDate(0, 0, -1)))
+	if err := os.WriteFile(yesterday, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Append(domain.AnalysisHistory{TraceID: "a"}); err == nil {
+		t.Fatal("append sealed onto an unreadable chain head")
+	}
+	if _, err := os.Stat(filepath.Join(store.Dir(), FileName(time.Now()))); !os.IsNotExist(err) {
+		t.Fatalf("today's file was written: %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+// 작업 상태
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+const (
+	defaultWorkers   = 4
+	defaultQueueSize = 64
+	defaultTimeout   = 2 * time.Minute
+	defaultResultTTL = 30 * time.Minute
+)
+
+var (
+	// ErrQueueFull - 대기열이 가득 차서 받을 수 없음 (429)
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrDuplicate - 같은 ID의 작업이 이미 있음
+	ErrDuplicate = errors.New("job already exists")
+)
+
+// RunFunc - 작업 본문. progress로 현재 단계를 알립니다.
+type RunFunc func(ctx context.Context, progress func(stage string)) (*domain.AnalysisResult, error)
+
+// Job - 폴링 응답으로 내보내는 작업 상태 (복사본)
+type Job struct {
+	ID         string                 `json:"id"`
+	Status     string                 `json:"status"`
+	Stage      string                 `json:"stage"`
+	Position   int                    `json:"position,omitempty"` // 대기 중일 때 순번 (1이면 다음 차례)
+	CreatedAt  time.Time              `json:"created_at"`
+	StartedAt  *time.Time             `json:"started_at,omitempty"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+	Result     *domain.AnalysisResult `json:"result,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+
+	err error
+	run RunFunc
+	seq uint64
+}
+
+// Err - 실패한 작업의 원래 에러 (상태 코드 판단용)
+func (j Job) Err() error {
+	return j.err
+}
+
+// Manager - 정해진 수의 워커로 작업을 처리하고, 끝난 작업은 ResultTTL 동안 조회할 수 있게 둡니다.
+type Manager struct {
+	cfg    config.JobConfig
+	logger *slog.Logger
+	queue  chan *Job
+
+	mu       sync.Mutex
+	jobs     map[string]*Job
+	enqueued uint64 // 대기 순번 계산용
+	started  uint64
+}
+
+func NewManager(cfg config.JobConfig, logger *slog.Logger) *Manager {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	return &Manager{
+		cfg:    cfg,
+		logger: logger.With("layer", "job"),
+		queue:  make(chan *Job, cfg.QueueSize),
+		jobs:   map[string]*Job{},
+	}
+}
+
+func (m *Manager) timeout() time.Duration {
+	if m.cfg.TimeoutSec > 0 {
+		return time.Duration(m.cfg.TimeoutSec) * time.Second
+	}
+	return defaultTimeout
+}
+
+func (m *Manager) resultTTL() time.Duration {
+	if m.cfg.ResultTTLMinutes > 0 {
+		return time.Duration(m.cfg.ResultTTLMinutes) * time.Minute
+	}
+	return defaultResultTTL
+}
+
+// Start - 워커와 만료 작업 정리 루프를 띄웁니다. ctx가 끝나면 새 작업을 꺼내지 않습니다.
+func (m *Manager) Start(ctx context.Context) {
+	for i := 0; i < m.cfg.Workers; i++ {
+		go m.worker(ctx)
+	}
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.sweep()
+			}
+		}
+	}()
+	m.logger.Info("Job workers started", "workers", m.cfg.Workers, "queue", m.cfg.QueueSize)
+}
+
+// Submit - 작업을 대기열에 넣습니다. 가득 차 있으면 ErrQueueFull.
+func (m *Manager) Submit(id string, run RunFunc) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.jobs[id]; ok {
+		return Job{}, ErrDuplicate
+	}
+
+	j := &Job{ID: id, Status: StatusQueued, Stage: StatusQueued, CreatedAt: time.Now(), run: run, seq: m.enqueued}
+	select {
+	case m.queue <- j:
+	default:
+		return Job{}, ErrQueueFull
+	}
+	m.enqueued++
+	m.jobs[id] = j
+	return m.snapshot(j), nil
+}
+
+// Get - 작업 상태 조회
+func (m *Manager) Get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return m.snapshot(j), true
+}
+
+// QueueLength - 대기 중인 작업 수
+func (m *Manager) QueueLength() int {
+	return len(m.queue)
+}
+
+func (m *Manager) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-m.queue:
+			m.process(j)
+		}
+	}
+}
+
+func (m *Manager) process(j *Job) {
+	now := time.Now()
+	m.update(j, func() {
+		j.Status = StatusRunning
+		j.StartedAt = &now
+		m.started++
+	})
+
+	// 요청한 클라이언트는 이미 떠났으므로 요청 context가 아니라 작업별 제한 시간을 씁니다.
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout())
+	defer cancel()
+
+	result, err := m.safeRun(ctx, j)
+
+	finished := time.Now()
+	m.update(j, func() {
+		j.FinishedAt = &finished
+		j.run = nil
+		if err != nil {
+			j.Status = StatusFailed
+			j.err = err
+			j.Error = err.Error()
+			return
+		}
+		j.Status = StatusSucceeded
+		j.Stage = "done"
+		j.Result = result
+	})
+}
+
+// safeRun - 작업 하나가 panic 나도 워커는 살아있도록 합니다.
+func (m *Manager) safeRun(ctx context.Context, j *Job) (result *domain.AnalysisResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.Error("Job panicked", "jobID", j.ID, "panic", r)
+			err = errors.New("internal error")
+		}
+	}()
+	return j.run(ctx, func(stage string) {
+		m.update(j, func() { j.Stage = stage })
+	})
+}
+
+func (m *Manager) update(j *Job, fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn()
+}
+
+// snapshot - 잠금 상태에서 호출합니다.
+func (m *Manager) snapshot(j *Job) Job {
+	out := *j
+	out.run = nil
+	if j.Status == StatusQueued {
+		out.Position = int(j.seq-m.started) + 1
+	}
+	return out
+}
+
+// sweep - 끝난 지 ResultTTL이 지난 작업을 지웁니다.
+func (m *Manager) sweep() {
+	cutoff := time.Now().Add(-m.resultTTL())
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, j := range m.jobs {
+		if j.FinishedAt != nil && j.FinishedAt.Before(cutoff) {
+			delete(m.jobs, id)
+		}
+	}
+}
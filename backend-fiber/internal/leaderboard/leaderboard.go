@@ -0,0 +1,287 @@
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/history"
+	"github.com/returnTesha/whois/internal/rewardpolicy"
+)
+
+// 집계 기간
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+	PeriodAll    = "all"
+)
+
+// 정렬 기준
+const (
+	ByBest  = "best"  // 최고 점수 → 먼저 달성한 쪽
+	ByCount = "count" // 고득점 수 → 최고 점수
+)
+
+const (
+	defaultThreshold = 95
+	defaultRefresh   = time.Minute
+	defaultLimit     = 20
+	defaultMaxLimit  = 100
+)
+
+var (
+	ErrInvalidPeriod = errors.New("period must be daily, weekly or all")
+	ErrInvalidSort   = errors.New("by must be best or count")
+)
+
+// Entry - 순위 한 줄
+type Entry struct {
+	Rank       int       `json:"rank"`
+	Player     string    `json:"player"`           // 보여줄 이름: 닉네임, 없으면 줄인 지갑 주소
+	Wallet     string    `json:"wallet,omitempty"` // 지갑 로그인(SIWE)으로 확인된 주소만
+	Nickname   string    `json:"nickname,omitempty"`
+	BestScore  float64   `json:"best_score"`
+	HighScores int       `json:"high_scores"` // 기준 점수 이상 그림 수
+	Attempts   int       `json:"attempts"`
+	AchievedAt time.Time `json:"achieved_at"` // 순위를 정한 기록의 시각 (동점이면 빠른 쪽이 위)
+}
+
+// Ranking - 한 기간의 순위표
+type Ranking struct {
+	Period    string     `json:"period"`
+	By        string     `json:"by"`
+	Since     *time.Time `json:"since,omitempty"` // all이면 없음
+	Threshold float64    `json:"threshold"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Entries   []Entry    `json:"entries"`
+}
+
+type attempt struct {
+	player   string // 확인된 지갑 주소(소문자) 또는 "nick:" + 닉네임
+	wallet   string
+	nickname string
+	score    float64
+	at       time.Time
+}
+
+// Board - 히스토리에서 순위를 계산합니다. 히스토리 전체를 읽는 비용이 크므로 Refresh 동안은 읽은 결과를 다시 씁니다.
+type Board struct {
+	cfg     config.LeaderboardConfig
+	store   *history.Store
+	loc     *time.Location
+	refresh time.Duration
+	logger  *slog.Logger
+
+	mu       sync.Mutex
+	attempts []attempt // 시간 순
+	loadedAt time.Time
+}
+
+func New(cfg config.LeaderboardConfig, store *history.Store, logger *slog.Logger) (*Board, error) {
+	loc := time.Local
+	if cfg.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("leaderboard: invalid timezone %q: %w", cfg.Timezone, err)
+		}
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = defaultThreshold
+	}
+	if cfg.MaxLimit <= 0 {
+		cfg.MaxLimit = defaultMaxLimit
+	}
+	refresh := time.Duration(cfg.RefreshSec) * time.Second
+	if refresh <= 0 {
+		refresh = defaultRefresh
+	}
+
+	return &Board{
+		cfg:     cfg,
+		store:   store,
+		loc:     loc,
+		refresh: refresh,
+		logger:  logger.With("layer", "leaderboard"),
+	}, nil
+}
+
+// Limit - 요청한 개수를 허용 범위로 맞춥니다.
+func (b *Board) Limit(requested int) int {
+	if requested <= 0 {
+		return defaultLimit
+	}
+	return min(requested, b.cfg.MaxLimit)
+}
+
+// Rank - 기간별 순위. 같은 점수면 그 점수(또는 개수)에 먼저 도달한 플레이어가 위입니다.
+func (b *Board) Rank(period, by string, limit int) (Ranking, error) {
+	if by == "" {
+		by = ByBest
+	}
+	if by != ByBest && by != ByCount {
+		return Ranking{}, ErrInvalidSort
+	}
+
+	now := time.Now().In(b.loc)
+	var since *time.Time
+	switch period {
+	case PeriodDaily:
+		start := startOfDay(now)
+		since = &start
+	case PeriodWeekly:
+		// 월요일 0시부터
+		start := startOfDay(now).AddDate(0, 0, -(int(now.Weekday())+6)%7)
+		since = &start
+	case PeriodAll, "":
+		period = PeriodAll
+	default:
+		return Ranking{}, ErrInvalidPeriod
+	}
+
+	attempts, updatedAt, err := b.load()
+	if err != nil {
+		return Ranking{}, err
+	}
+
+	entries := map[string]*Entry{}
+	countAt := map[string]time.Time{} // 마지막 고득점 시각 (count 정렬의 동점 처리용)
+	for _, a := range attempts {
+		if since != nil && a.at.Before(*since) {
+			continue
+		}
+		e := entries[a.player]
+		if e == nil {
+			e = &Entry{Wallet: a.wallet, BestScore: -1}
+			entries[a.player] = e
+		}
+		if a.nickname != "" {
+			e.Nickname = a.nickname // 가장 최근 닉네임
+		}
+		e.Attempts++
+		if a.score > e.BestScore {
+			e.BestScore = a.score
+			e.AchievedAt = a.at
+		}
+		if a.score >= b.cfg.Threshold {
+			e.HighScores++
+			countAt[a.player] = a.at
+		}
+	}
+
+	ranked := make([]Entry, 0, len(entries))
+	for player, e := range entries {
+		if by == ByCount {
+			if e.HighScores == 0 {
+				continue
+			}
+			e.AchievedAt = countAt[player]
+		}
+		e.Player = displayName(e.Nickname, e.Wallet)
+		ranked = append(ranked, *e)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		x, y := ranked[i], ranked[j]
+		if by == ByCount && x.HighScores != y.HighScores {
+			return x.HighScores > y.HighScores
+		}
+		if x.BestScore != y.BestScore {
+			return x.BestScore > y.BestScore
+		}
+		if !x.AchievedAt.Equal(y.AchievedAt) {
+			return x.AchievedAt.Before(y.AchievedAt)
+		}
+		return x.Player < y.Player
+	})
+
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+
+	return Ranking{
+		Period:    period,
+		By:        by,
+		Since:     since,
+		Threshold: b.cfg.Threshold,
+		UpdatedAt: updatedAt,
+		Entries:   ranked,
+	}, nil
+}
+
+// load - Refresh가 지났으면 히스토리를 다시 읽습니다.
+func (b *Board) load() ([]attempt, time.Time, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.loadedAt.IsZero() && time.Since(b.loadedAt) < b.refresh {
+		return b.attempts, b.loadedAt, nil
+	}
+
+	var attempts []attempt
+	err := b.store.Scan(func(h domain.AnalysisHistory) bool {
+		if a, ok := toAttempt(h); ok {
+			attempts = append(attempts, a)
+		}
+		return true
+	})
+	if err != nil {
+		b.logger.Error("리더보드 집계 실패", "error", err)
+		if b.loadedAt.IsZero() {
+			return nil, time.Time{}, err
+		}
+		// 이전 결과라도 보여줍니다.
+		return b.attempts, b.loadedAt, nil
+	}
+
+	b.attempts = attempts
+	b.loadedAt = time.Now()
+	return b.attempts, b.loadedAt, nil
+}
+
+// toAttempt - 순위에 넣을 수 있는 기록만 고릅니다.
+// 실패, 봇, 재제출(캐시), 대체 분석기 추정 점수, 중복으로 막힌 그림은 세지 않습니다.
+// 지갑은 로그인으로 확인된 기록에서만 씁니다. 확인되지 않은 기록은 닉네임 플레이어로만 봅니다. (남의 지갑으로 점수를 올리거나 이름을 바꾸지 못하도록)
+func toAttempt(h domain.AnalysisHistory) (attempt, bool) {
+	if h.Redacted || h.Status != 200 || h.IsBot || h.Cached || h.Fallback || h.RewardDecision == rewardpolicy.ActionBlock {
+		return attempt{}, false
+	}
+	if !h.WalletVerified {
+		h.Wallet = ""
+	}
+	if h.Wallet == "" && h.Nickname == "" {
+		return attempt{}, false
+	}
+	at, err := time.Parse(time.RFC3339, h.Timestamp)
+	if err != nil {
+		return attempt{}, false
+	}
+
+	player := strings.ToLower(h.Wallet)
+	if player == "" {
+		player = "nick:" + strings.ToLower(h.Nickname)
+	}
+	return attempt{player: player, wallet: h.Wallet, nickname: h.Nickname, score: h.Similarity, at: at}, true
+}
+
+func displayName(nickname, wallet string) string {
+	if nickname != "" {
+		return nickname
+	}
+	if len(wallet) > 10 {
+		return wallet[:6] + "…" + wallet[len(wallet)-4:]
+	}
+	return wallet
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,166 @@
+package livefeed
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+)
+
+const (
+	defaultMaxConnections = 500
+	defaultMaxPerIP       = 5
+	defaultReplay         = 20
+	clientBuffer          = 32
+)
+
+var (
+	// ErrTooManyConnections - 전체 연결 수 한도 초과 (503)
+	ErrTooManyConnections = errors.New("live feed is full")
+	// ErrTooManyFromIP - 같은 IP의 연결 수 한도 초과 (429)
+	ErrTooManyFromIP = errors.New("too many live feed connections from this address")
+)
+
+// Attempt - 공개 점수판에 보여주는 분석 한 건. 누가 그렸는지 알 수 있는 값(IP, traceID 등)은 넣지 않습니다.
+type Attempt struct {
+	Time       time.Time `json:"time"`
+	Challenge  string    `json:"challenge,omitempty"`
+	Similarity float64   `json:"similarity"`
+	Device     string    `json:"device"`
+	Rewarded   bool      `json:"rewarded"`
+	Tx         string    `json:"tx,omitempty"` // 앞뒤만 남긴 트랜잭션 해시
+}
+
+// Message - 클라이언트로 보내는 메시지. 연결 직후 replay 한 번, 이후 attempt가 옵니다.
+type Message struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// Client - 연결 하나. Messages가 닫히면 (너무 느려서 끊긴 경우 포함) 연결을 닫아야 합니다.
+type Client struct {
+	ip     string
+	send   chan []byte
+	closed bool
+}
+
+func (c *Client) Messages() <-chan []byte {
+	return c.send
+}
+
+// Hub - 최근 분석 결과를 연결된 모든 클라이언트에 뿌립니다.
+type Hub struct {
+	cfg    config.LiveFeedConfig
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	recent  []Attempt
+	clients map[*Client]struct{}
+	perIP   map[string]int
+}
+
+func NewHub(cfg config.LiveFeedConfig, logger *slog.Logger) *Hub {
+	if cfg.MaxConnections <= 0 {
+		cfg.MaxConnections = defaultMaxConnections
+	}
+	if cfg.MaxPerIP <= 0 {
+		cfg.MaxPerIP = defaultMaxPerIP
+	}
+	if cfg.Replay <= 0 {
+		cfg.Replay = defaultReplay
+	}
+	return &Hub{
+		cfg:     cfg,
+		logger:  logger.With("layer", "livefeed"),
+		clients: map[*Client]struct{}{},
+		perIP:   map[string]int{},
+	}
+}
+
+// Join - 연결을 등록하고 지금까지의 최근 기록(replay)을 돌려줍니다. 한도를 넘으면 에러.
+func (h *Hub) Join(ip string) (*Client, []byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.clients) >= h.cfg.MaxConnections {
+		return nil, nil, ErrTooManyConnections
+	}
+	if h.perIP[ip] >= h.cfg.MaxPerIP {
+		return nil, nil, ErrTooManyFromIP
+	}
+
+	replay, err := json.Marshal(Message{Type: "replay", Data: append([]Attempt{}, h.recent...)})
+	if err != nil {
+		return nil, nil, err
+	}
+	c := &Client{ip: ip, send: make(chan []byte, clientBuffer)}
+	h.clients[c] = struct{}{}
+	h.perIP[ip]++
+	return c, replay, nil
+}
+
+// Leave - 연결이 끝나면 호출합니다. 여러 번 불러도 됩니다.
+func (h *Hub) Leave(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drop(c)
+}
+
+// Publish - 분석 한 건을 기록하고 모든 연결에 보냅니다.
+func (h *Hub) Publish(a Attempt) {
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+	msg, err := json.Marshal(Message{Type: "attempt", Data: a})
+	if err != nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.recent = append(h.recent, a)
+	if len(h.recent) > h.cfg.Replay {
+		h.recent = append(h.recent[:0], h.recent[len(h.recent)-h.cfg.Replay:]...)
+	}
+	for c := range h.clients {
+		select {
+		case c.send <- msg:
+		default:
+			// 버퍼가 찼다면 따라오지 못하는 연결이므로 끊습니다. (발행하는 쪽을 막지 않기 위해)
+			h.logger.Warn("느린 연결 종료", "ip", c.ip)
+			h.drop(c)
+		}
+	}
+}
+
+// Connections - 현재 연결 수
+func (h *Hub) Connections() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// drop - 잠금 상태에서 호출합니다.
+func (h *Hub) drop(c *Client) {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.send)
+	delete(h.clients, c)
+	if h.perIP[c.ip]--; h.perIP[c.ip] <= 0 {
+		delete(h.perIP, c.ip)
+	}
+}
+
+// TruncateTx - "0x1234ab…89cdef"처럼 앞뒤만 남깁니다.
+func TruncateTx(tx string) string {
+	if len(tx) <= 14 {
+		return tx
+	}
+	return tx[:8] + "…" + tx[len(tx)-6:]
+}
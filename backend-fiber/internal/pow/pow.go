@@ -0,0 +1,263 @@
+package pow
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/config"
+)
+
+// 적용 방식
+const (
+	ModeAnalyze = "analyze"
+	ModeReward  = "reward"
+)
+
+// 미들웨어가 남기는 풀이 상태
+const (
+	StatusSolved  = "solved"
+	StatusMissing = "missing"
+)
+
+// DecisionRequired - reward 모드에서 풀이 없이 보낸 그림의 보상 판정
+const DecisionRequired = "pow_required"
+
+// LocalsKey - 풀이 상태를 저장하는 fiber Locals 키
+const LocalsKey = "pow"
+
+// 풀이를 싣는 헤더
+const (
+	HeaderChallenge = "X-PoW-Challenge"
+	HeaderNonce     = "X-PoW-Nonce"
+)
+
+const (
+	version          = "v1"
+	defaultTTL       = 2 * time.Minute
+	defaultBaseBits  = 16
+	defaultMaxBits   = 24
+	defaultWindow    = time.Minute
+	defaultBaseline  = 60
+	maxNonceLength   = 64
+	maxUsed          = 100000
+	maxSupportedBits = 32
+)
+
+var (
+	// ErrMissing - analyze 모드에서 풀이 없이 요청함 (428)
+	ErrMissing = errors.New("proof of work required")
+	// ErrInvalid - 챌린지가 위조/만료/재사용되었거나 풀이가 난이도를 못 채움 (400)
+	ErrInvalid = errors.New("invalid proof of work")
+)
+
+// Challenge - GET /pow 응답. 클라이언트는 sha256(challenge + ":" + nonce)의 앞 difficulty 비트가 0인 nonce를 찾습니다.
+type Challenge struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	Algorithm  string    `json:"algorithm"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Service - 서명된 챌린지 발급과 풀이 확인. 챌린지는 한 번만 쓸 수 있습니다.
+type Service struct {
+	mode     string
+	secret   []byte
+	ttl      time.Duration
+	baseBits int
+	maxBits  int
+	baseline int
+	volume   *volume
+	logger   *slog.Logger
+
+	mu   sync.Mutex
+	used map[string]time.Time // 챌린지 id → 만료 시각
+}
+
+func NewService(cfg config.PoWConfig, logger *slog.Logger) (*Service, error) {
+	if len(cfg.Secret) < 32 {
+		return nil, fmt.Errorf("pow: secret must be at least 32 bytes")
+	}
+	mode := cfg.Mode
+	if mode == "" {
+		mode = ModeReward
+	}
+	if mode != ModeAnalyze && mode != ModeReward {
+		return nil, fmt.Errorf("pow: unknown mode %q", cfg.Mode)
+	}
+	s := &Service{
+		mode:     mode,
+		secret:   []byte(cfg.Secret),
+		ttl:      time.Duration(cfg.TTLSec) * time.Second,
+		baseBits: cfg.BaseBits,
+		maxBits:  cfg.MaxBits,
+		baseline: cfg.Baseline,
+		logger:   logger.With("layer", "pow"),
+		used:     map[string]time.Time{},
+	}
+	if s.ttl <= 0 {
+		s.ttl = defaultTTL
+	}
+	if s.baseBits <= 0 {
+		s.baseBits = defaultBaseBits
+	}
+	if s.maxBits <= 0 {
+		s.maxBits = defaultMaxBits
+	}
+	if s.maxBits < s.baseBits || s.maxBits > maxSupportedBits {
+		return nil, fmt.Errorf("pow: max_bits must be between base_bits and %d", maxSupportedBits)
+	}
+	if s.baseline <= 0 {
+		s.baseline = defaultBaseline
+	}
+	window := time.Duration(cfg.WindowSec) * time.Second
+	if window <= 0 {
+		window = defaultWindow
+	}
+	s.volume = newVolume(window)
+	return s, nil
+}
+
+// Difficulty - 요청량이 baseline의 두 배가 될 때마다 1비트씩 (= 풀이 비용 두 배) 올립니다.
+func (s *Service) Difficulty(now time.Time) int {
+	n := s.volume.count(now)
+	if n <= s.baseline {
+		return s.baseBits
+	}
+	extra := int(math.Ceil(math.Log2(float64(n) / float64(s.baseline))))
+	return min(s.maxBits, s.baseBits+extra)
+}
+
+// Issue - 현재 난이도로 챌린지를 만듭니다. 형식: base64(v1:id:bits:expiry).서명
+func (s *Service) Issue(now time.Time) (Challenge, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Challenge{}, err
+	}
+	difficulty := s.Difficulty(now)
+	expiresAt := now.Add(s.ttl)
+
+	payload := base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%s:%s:%d:%d", version, hex.EncodeToString(id), difficulty, expiresAt.Unix()))
+	return Challenge{
+		Challenge:  payload + "." + s.sign(payload),
+		Difficulty: difficulty,
+		Algorithm:  "sha256",
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// Verify - 서명, 만료, 난이도를 확인하고 챌린지를 사용 처리합니다.
+func (s *Service) Verify(challenge, nonce string, now time.Time) error {
+	if nonce == "" || len(nonce) > maxNonceLength {
+		return fmt.Errorf("%w: nonce must be 1-%d characters", ErrInvalid, maxNonceLength)
+	}
+	payload, sig, ok := strings.Cut(challenge, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(payload))) {
+		return fmt.Errorf("%w: bad challenge signature", ErrInvalid)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return fmt.Errorf("%w: malformed challenge", ErrInvalid)
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 4 || parts[0] != version {
+		return fmt.Errorf("%w: malformed challenge", ErrInvalid)
+	}
+	difficulty, err1 := strconv.Atoi(parts[2])
+	expiry, err2 := strconv.ParseInt(parts[3], 10, 64)
+	if err1 != nil || err2 != nil {
+		return fmt.Errorf("%w: malformed challenge", ErrInvalid)
+	}
+	expiresAt := time.Unix(expiry, 0)
+	if !now.Before(expiresAt) {
+		return fmt.Errorf("%w: challenge expired", ErrInvalid)
+	}
+
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	if leadingZeroBits(sum[:]) < difficulty {
+		return fmt.Errorf("%w: solution does not meet difficulty %d", ErrInvalid, difficulty)
+	}
+	// 풀이까지 맞은 뒤에 사용 처리합니다.
+	if !s.markUsed(parts[1], expiresAt, now) {
+		return fmt.Errorf("%w: challenge already used", ErrInvalid)
+	}
+	return nil
+}
+
+// Middleware - 분석 요청량을 세고 풀이를 확인합니다. analyze 모드에서는 풀이가 없으면 428로 돌려보냅니다.
+func (s *Service) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		now := time.Now()
+		s.volume.add(now)
+
+		challenge, nonce := c.Get(HeaderChallenge), c.Get(HeaderNonce)
+		if challenge == "" {
+			if s.mode == ModeAnalyze {
+				return c.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{"error": ErrMissing.Error()})
+			}
+			c.Locals(LocalsKey, StatusMissing)
+			return c.Next()
+		}
+		if err := s.Verify(challenge, nonce, now); err != nil {
+			s.logger.Info("작업 증명 실패", "error", err, "path", c.Path())
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		c.Locals(LocalsKey, StatusSolved)
+		return c.Next()
+	}
+}
+
+// StatusFromCtx - 풀이 상태. 작업 증명을 쓰지 않으면 빈 문자열
+func StatusFromCtx(c *fiber.Ctx) string {
+	status, _ := c.Locals(LocalsKey).(string)
+	return status
+}
+
+func (s *Service) markUsed(id string, expiresAt, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.used[id]; ok {
+		return false
+	}
+	if len(s.used) >= maxUsed {
+		for k, exp := range s.used {
+			if now.After(exp) {
+				delete(s.used, k)
+			}
+		}
+	}
+	if len(s.used) >= maxUsed {
+		return false
+	}
+	s.used[id] = expiresAt
+	return true
+}
+
+func (s *Service) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func leadingZeroBits(sum []byte) int {
+	n := 0
+	for _, b := range sum {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
@@ -0,0 +1,47 @@
+package pow
+
+import (
+	"sync"
+	"time"
+)
+
+// volume - 최근 window 동안의 요청 수 (초 단위 칸을 돌려 씁니다)
+type volume struct {
+	mu      sync.Mutex
+	slots   []int
+	lastSec int64
+}
+
+func newVolume(window time.Duration) *volume {
+	return &volume{slots: make([]int, max(1, int(window/time.Second)))}
+}
+
+func (v *volume) add(now time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.advance(now.Unix())
+	v.slots[now.Unix()%int64(len(v.slots))]++
+}
+
+func (v *volume) count(now time.Time) int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.advance(now.Unix())
+	total := 0
+	for _, n := range v.slots {
+		total += n
+	}
+	return total
+}
+
+// advance - 지난 번 이후로 지나간 칸을 비웁니다.
+func (v *volume) advance(sec int64) {
+	if sec <= v.lastSec {
+		return
+	}
+	size := int64(len(v.slots))
+	for s := max(v.lastSec+1, sec-size+1); s <= sec; s++ {
+		v.slots[s%size] = 0
+	}
+	v.lastSec = sec
+}
@@ -0,0 +1,115 @@
+package privacy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/returnTesha/whois/config"
+)
+
+const (
+	IPModeRaw      = "raw"
+	IPModeHash     = "hash"
+	IPModeTruncate = "truncate"
+
+	UAModeRaw        = "raw"
+	UAModeGeneralize = "generalize"
+	UAModeDrop       = "drop"
+
+	hashPrefix = "hmac:"
+)
+
+// Anonymizer - 히스토리에 남기기 전에 IP와 User-Agent를 설정된 방식으로 가공합니다.
+type Anonymizer struct {
+	ipMode string
+	uaMode string
+	key    []byte
+}
+
+func NewAnonymizer(cfg config.PrivacyConfig) (*Anonymizer, error) {
+	a := &Anonymizer{
+		ipMode: cfg.IPMode,
+		uaMode: cfg.UAMode,
+		key:    []byte(cfg.HashKey),
+	}
+	if a.ipMode == "" {
+		a.ipMode = IPModeRaw
+	}
+	if a.uaMode == "" {
+		a.uaMode = UAModeRaw
+	}
+
+	switch a.ipMode {
+	case IPModeRaw, IPModeTruncate:
+	case IPModeHash:
+		if len(a.key) == 0 {
+			return nil, fmt.Errorf("privacy: hash_key is required when ip_mode is %q", IPModeHash)
+		}
+		// IPv4는 전부 대입해 볼 수 있으므로 키가 알려져 있으면 해시를 되돌릴 수 있습니다.
+		if len(a.key) < 32 || config.IsPlaceholder(cfg.HashKey) {
+			return nil, fmt.Errorf("privacy: hash_key must be a random secret of at least 32 bytes")
+		}
+	default:
+		return nil, fmt.Errorf("privacy: unknown ip_mode %q", a.ipMode)
+	}
+
+	switch a.uaMode {
+	case UAModeRaw, UAModeGeneralize, UAModeDrop:
+	default:
+		return nil, fmt.Errorf("privacy: unknown ua_mode %q", a.uaMode)
+	}
+
+	return a, nil
+}
+
+// IP - 설정에 따라 원본, HMAC 해시, 또는 서브넷(/24, /48)으로 바꿉니다.
+func (a *Anonymizer) IP(ip string) string {
+	ip = strings.TrimSpace(ip)
+	switch a.ipMode {
+	case IPModeHash:
+		return a.HashIP(ip)
+	case IPModeTruncate:
+		return truncateIP(ip)
+	default:
+		return ip
+	}
+}
+
+// IPIdentifies - 저장된 IP 값이 한 사람을 가리키는지. truncate면 서브넷 전체가 같은 값이 됩니다.
+func (a *Anonymizer) IPIdentifies() bool {
+	return a.ipMode != IPModeTruncate
+}
+
+// HashIP - 키 기반 HMAC-SHA256 해시. 삭제 요청 시 원본 IP로 같은 값을 다시 만들 수 있습니다.
+func (a *Anonymizer) HashIP(ip string) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(strings.TrimSpace(ip)))
+	return hashPrefix + hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// UserAgent - 원본을 유지하거나, 브라우저/OS 수준으로 일반화하거나, 버립니다.
+func (a *Anonymizer) UserAgent(ua, browser, os string) string {
+	switch a.uaMode {
+	case UAModeGeneralize:
+		return browser + " on " + os
+	case UAModeDrop:
+		return ""
+	default:
+		return ua
+	}
+}
+
+func truncateIP(raw string) string {
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return "unknown"
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}).String()
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/events"
 	"github.com/returnTesha/whois/pkg/logger"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -57,9 +59,19 @@ func (p *PolygonProvider) Excute(ctx context.Context, data interface{}, traceID
 	}
 	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
 
-	// 4. 랜덤 토큰 수량 결정 (1 ~ 1000)
+	// 4. 랜덤 토큰 수량 결정 (기본 1 ~ 1000, 도전의 보상 등급이 있으면 그 범위)
 	rand.Seed(time.Now().UnixNano())
-	randomAmount := rand.Intn(1000) + 1
+	minAmount, maxAmount := 1, 1000
+	reward, isReward := data.(domain.RewardRequest)
+	if isReward && reward.MinAmount > 0 && reward.MaxAmount >= reward.MinAmount {
+		minAmount, maxAmount = reward.MinAmount, reward.MaxAmount
+	}
+	randomAmount := rand.Intn(maxAmount-minAmount+1) + minAmount
+
+	// 보상 정책이 감액을 정했으면 비율만큼 줄입니다. (최소 1)
+	if isReward && reward.Multiplier > 0 && reward.Multiplier < 1 {
+		randomAmount = max(1, int(float64(randomAmount)*reward.Multiplier))
+	}
 
 	// 토큰의 Decimals가 18이라고 가정 (일반적)
 	// amount = randomAmount * 10^18
@@ -68,6 +80,10 @@ func (p *PolygonProvider) Excute(ctx context.Context, data interface{}, traceID
 	// 5. ERC-20 Transfer 데이터 생성
 	tokenAddress := common.HexToAddress(tokenAddressHex)
 	toAddress := common.HexToAddress(toAddressHex)
+	if isReward && common.IsHexAddress(reward.Recipient) {
+		// 지갑 로그인으로 확인된 수령인
+		toAddress = common.HexToAddress(reward.Recipient)
+	}
 	transferFnSignature := []byte("transfer(address,uint256)")
 	methodID := crypto.Keccak256(transferFnSignature)[:4]
 	paddedAddress := common.LeftPadBytes(toAddress.Bytes(), 32)
@@ -108,5 +124,47 @@ func (p *PolygonProvider) Excute(ctx context.Context, data interface{}, traceID
 	txHash := signedTx.Hash().Hex()
 	p.logger.Info("토큰 전송 완료", "txHash", txHash, "amount", randomAmount)
 
+	emit := events.FromContext(ctx)
+	emit(events.StageTxSent, map[string]any{"tx_hash": txHash, "amount": randomAmount}, false)
+	// 확정은 응답을 붙잡지 않고 따로 기다립니다. (요청 context는 곧 끝나므로 쓰지 않음)
+	go p.waitReceipt(client, signedTx.Hash(), traceID, emit)
+
 	return txHash, nil
 }
+
+const (
+	receiptTimeout  = 2 * time.Minute
+	receiptInterval = 3 * time.Second
+)
+
+// waitReceipt - 영수증이 나올 때까지 기다렸다가 tx_confirmed(또는 failed) 이벤트를 냅니다.
+func (p *PolygonProvider) waitReceipt(client *ethclient.Client, hash common.Hash, traceID string, emit events.Emitter) {
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), receiptTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(receiptInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := client.TransactionReceipt(ctx, hash)
+		if err == nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				p.logger.Warn("트랜잭션 실패", "txHash", hash.Hex(), "traceID", traceID)
+				emit(events.StageFailed, map[string]any{"tx_hash": hash.Hex(), "error": "transaction reverted"}, true)
+				return
+			}
+			p.logger.Info("트랜잭션 확정", "txHash", hash.Hex(), "block", receipt.BlockNumber, "traceID", traceID)
+			emit(events.StageTxConfirmed, map[string]any{"tx_hash": hash.Hex(), "block": receipt.BlockNumber.Uint64()}, true)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			p.logger.Warn("트랜잭션 확정 대기 시간 초과", "txHash", hash.Hex(), "traceID", traceID)
+			emit(events.StageFailed, map[string]any{"tx_hash": hash.Hex(), "error": "confirmation timed out"}, true)
+			return
+		case <-ticker.C:
+		}
+	}
+}
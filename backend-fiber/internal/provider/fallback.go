@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+// FallbackProvider - 주 분석기가 실패하면 대체 분석기로 점수를 내고, 결과에 대체 여부를 표시합니다.
+type FallbackProvider struct {
+	primary  Provider
+	fallback Provider
+	logger   *slog.Logger
+}
+
+func WithFallback(primary, fallback Provider, logger *slog.Logger) *FallbackProvider {
+	return &FallbackProvider{
+		primary:  primary,
+		fallback: fallback,
+		logger:   logger.With("provider", primary.GetName(), "fallback", fallback.GetName()),
+	}
+}
+
+// GetName - 레지스트리/로그에서는 주 분석기 이름으로 보입니다.
+func (p *FallbackProvider) GetName() string {
+	return p.primary.GetName()
+}
+
+func (p *FallbackProvider) Excute(ctx context.Context, data interface{}, traceID string) (interface{}, error) {
+	resRaw, err := p.primary.Excute(ctx, data, traceID)
+	if err == nil {
+		if result, ok := resRaw.(domain.AnalysisResult); ok && result.Analyzer == "" {
+			result.Analyzer = p.primary.GetName()
+			return result, nil
+		}
+		return resRaw, nil
+	}
+
+	// 사용자가 요청을 끊은 경우에는 대체하지 않습니다.
+	if errors.Is(err, context.Canceled) {
+		return nil, err
+	}
+
+	p.logger.Warn("Primary analyzer failed, using fallback", "traceID", traceID, "error", err)
+	fbRaw, fbErr := p.fallback.Excute(ctx, data, traceID)
+	if fbErr != nil {
+		p.logger.Error("Fallback analyzer failed", "traceID", traceID, "error", fbErr)
+		return nil, err
+	}
+
+	result, ok := fbRaw.(domain.AnalysisResult)
+	if !ok {
+		return nil, err
+	}
+	result.Analyzer = p.fallback.GetName()
+	result.Fallback = true
+	result.FallbackReason = err.Error()
+	return result, nil
+}
@@ -0,0 +1,264 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/provider"
+	"github.com/returnTesha/whois/pkg/breaker"
+	"github.com/returnTesha/whois/pkg/dataurl"
+	"github.com/returnTesha/whois/pkg/logger"
+)
+
+const (
+	defaultEndpoint = "https://generativelanguage.googleapis.com"
+	defaultModel    = "gemini-2.0-flash"
+)
+
+// TargetPlaceholder - 프롬프트 템플릿에서 도전의 목표 설명이 들어갈 자리
+const TargetPlaceholder = "{target}"
+
+// DefaultPrompt - Spring GeminiAnalysisService와 같은 프롬프트 (응답 JSON 계약을 맞추기 위해)
+const DefaultPrompt = "Analyze the similarity of the handwritten drawing in the image to " + TargetPlaceholder + ". " + responseContract
+
+// defaultTarget - 도전이 정해지지 않은 요청의 목표 (기존 물음표 게임)
+const defaultTarget = "a question mark symbol '?'"
+
+const responseContract = "You must respond in JSON format with the following keys: " +
+	"'similarity' (a number between 0 and 100), " +
+	"'feedback' (helpful feedback in English), " +
+	"'feedback_ko' (the same feedback translated into natural Korean). " +
+	"Ensure the Korean translation sounds friendly and encouraging."
+
+// PromptFor - 템플릿의 {target} 자리에 도전의 목표 설명(예: "an at sign symbol '@'")을 넣습니다.
+func PromptFor(template, target string) string {
+	if target == "" {
+		target = defaultTarget
+	}
+	return strings.ReplaceAll(template, TargetPlaceholder, target)
+}
+
+// GeminiProvider - Spring을 거치지 않고 Gemini generateContent API를 직접 호출합니다.
+type GeminiProvider struct {
+	cfg     config.GeminiConfig
+	logger  *slog.Logger
+	name    string
+	client  *http.Client
+	breaker *breaker.Breaker
+}
+
+func NewProvider(cfg config.GeminiConfig, baseLogger *slog.Logger) (*GeminiProvider, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("gemini: api_key is required")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultEndpoint
+	}
+	if cfg.Model == "" {
+		cfg.Model = defaultModel
+	}
+	if cfg.Prompt == "" {
+		cfg.Prompt = DefaultPrompt
+	}
+	// 목표 자리가 없으면 도전마다 같은 질문을 하게 되므로 거부합니다.
+	if !strings.Contains(cfg.Prompt, TargetPlaceholder) {
+		return nil, fmt.Errorf("gemini: prompt must contain %s", TargetPlaceholder)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 60000
+	}
+
+	return &GeminiProvider{
+		cfg:     cfg,
+		logger:  logger.WithProvider(baseLogger, "gemini"),
+		name:    "gemini",
+		client:  &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Millisecond},
+		breaker: breaker.New(cfg.BreakerThreshold, time.Duration(cfg.BreakerOpenSec)*time.Second),
+	}, nil
+}
+
+func (p *GeminiProvider) GetName() string {
+	return p.name
+}
+
+// generateContent 요청/응답 (필요한 필드만)
+type part struct {
+	Text       string      `json:"text,omitempty"`
+	InlineData *inlineData `json:"inline_data,omitempty"`
+}
+
+type inlineData struct {
+	MimeType string `json:"mime_type"`
+	Data     string `json:"data"`
+}
+
+type content struct {
+	Role  string `json:"role,omitempty"`
+	Parts []part `json:"parts"`
+}
+
+type generateRequest struct {
+	Contents         []content        `json:"contents"`
+	GenerationConfig generationConfig `json:"generationConfig"`
+}
+
+type generationConfig struct {
+	ResponseMimeType string  `json:"responseMimeType"`
+	Temperature      float64 `json:"temperature"`
+}
+
+type generateResponse struct {
+	Candidates []struct {
+		Content      content `json:"content"`
+		FinishReason string  `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback *struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback,omitempty"`
+}
+
+type apiError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+func (p *GeminiProvider) Excute(ctx context.Context, data interface{}, traceID string) (interface{}, error) {
+	req, ok := data.(domain.DrawingRequest)
+	if !ok {
+		return nil, fmt.Errorf("invalid request type")
+	}
+
+	mime, image, err := dataurl.Decode(req.ImageData)
+	if err != nil {
+		return nil, err
+	}
+
+	// 도전이 정해져 있으면 그 도형 기준으로 묻습니다.
+	prompt := PromptFor(p.cfg.Prompt, req.Prompt)
+
+	body, err := json.Marshal(generateRequest{
+		Contents: []content{{
+			Role: "user",
+			Parts: []part{
+				{Text: prompt},
+				{InlineData: &inlineData{MimeType: mime, Data: base64.StdEncoding.EncodeToString(image)}},
+			},
+		}},
+		GenerationConfig: generationConfig{
+			ResponseMimeType: "application/json",
+			Temperature:      p.cfg.Temperature,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("json marshal error: %w", err)
+	}
+
+	if err := p.breaker.Allow(); err != nil {
+		p.logger.Warn("Gemini circuit open, skipping call", "traceID", traceID)
+		return nil, fmt.Errorf("gemini: %w", err)
+	}
+
+	result, err := p.call(ctx, body, traceID)
+	if errors.Is(err, context.Canceled) {
+		p.breaker.Release()
+	} else {
+		var statusErr *StatusError
+		clientErr := errors.As(err, &statusErr) && statusErr.StatusCode < 500 && statusErr.StatusCode != http.StatusTooManyRequests
+		p.breaker.Done(err == nil || clientErr)
+	}
+	if err != nil {
+		p.logger.Error("Gemini call failed", "traceID", traceID, "error", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// StatusError - Gemini API가 200이 아닌 응답을 준 경우
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("gemini returned status: %d %s", e.StatusCode, e.Message)
+}
+
+func (p *GeminiProvider) call(ctx context.Context, body []byte, traceID string) (domain.AnalysisResult, error) {
+	var result domain.AnalysisResult
+
+	url := fmt.Sprintf("%s/v1beta/models/%s:generateContent", strings.TrimRight(p.cfg.Endpoint, "/"), p.cfg.Model)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return result, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", p.cfg.APIKey)
+	httpReq.Header.Set("X-Trace-ID", traceID)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return result, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		_ = json.Unmarshal(raw, &apiErr)
+		return result, &StatusError{StatusCode: resp.StatusCode, Message: apiErr.Error.Message}
+	}
+
+	var gen generateResponse
+	if err := json.Unmarshal(raw, &gen); err != nil {
+		return result, fmt.Errorf("gemini: decode response: %w", err)
+	}
+	if gen.PromptFeedback != nil && gen.PromptFeedback.BlockReason != "" {
+		return result, fmt.Errorf("gemini: prompt blocked: %s", gen.PromptFeedback.BlockReason)
+	}
+	if len(gen.Candidates) == 0 || len(gen.Candidates[0].Content.Parts) == 0 {
+		return result, fmt.Errorf("gemini: empty response")
+	}
+
+	var text strings.Builder
+	for _, part := range gen.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+
+	// 모델이 ```json 코드 블록으로 감싸는 경우가 있어 벗겨냅니다.
+	return provider.DecodeResult(p.name, []byte(stripFence(text.String())))
+}
+
+func stripFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	s = strings.TrimPrefix(s, "json")
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+}
+
+func (p *GeminiProvider) Health() provider.Health {
+	return provider.BreakerHealth(p.name, p.breaker.Stats(), map[string]any{
+		"model":      p.cfg.Model,
+		"timeout_ms": p.cfg.Timeout,
+	})
+}
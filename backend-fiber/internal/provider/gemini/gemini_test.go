@@ -0,0 +1,170 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+// 1x1 PNG
+const testImage = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg=="
+
+// newStub - generateContent 요청을 확인하고 status/body로 답하는 로컬 Gemini 서버
+func newStub(t *testing.T, status int, body string) (*GeminiProvider, *[]generateRequest) {
+	t.Helper()
+	return newPromptStub(t, status, body, "")
+}
+
+// newPromptStub - newStub과 같지만 프롬프트 템플릿을 지정합니다.
+func newPromptStub(t *testing.T, status int, body, prompt string) (*GeminiProvider, *[]generateRequest) {
+	t.Helper()
+	var received []generateRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/models/test-model:generateContent" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.Header.Get("x-goog-api-key"); got != "test-key" {
+			t.Errorf("api key header = %q", got)
+		}
+		raw, _ := io.ReadAll(r.Body)
+		var req generateRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		received = append(received, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	p, err := NewProvider(config.GeminiConfig{
+		Endpoint: srv.URL,
+		Model:    "test-model",
+		APIKey:   "test-key",
+		Prompt:   prompt,
+		Timeout:  5000,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, &received
+}
+
+// candidate - 모델이 text로 돌려준 응답 하나를 generateContent 형식으로 감쌉니다.
+func candidate(text string) string {
+	body, _ := json.Marshal(map[string]any{
+		"candidates": []map[string]any{{
+			"content":      map[string]any{"role": "model", "parts": []map[string]any{{"text": text}}},
+			"finishReason": "STOP",
+		}},
+	})
+	return string(body)
+}
+
+func TestExcuteSuccess(t *testing.T) {
+	p, received := newStub(t, http.StatusOK, candidate(`{"similarity": 87.5, "feedback": "Nice curve", "feedback_ko": "좋은 곡선이에요"}`))
+
+	out, err := p.Excute(context.Background(), domain.DrawingRequest{ImageData: testImage, Prompt: "an at sign symbol '@'"}, "trace-1")
+	if err != nil {
+		t.Fatalf("Excute: %v", err)
+	}
+	result := out.(domain.AnalysisResult)
+	if result.Similarity != 87.5 || result.Feedback != "Nice curve" || result.FeedbackKo != "좋은 곡선이에요" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if len(*received) != 1 {
+		t.Fatalf("stub received %d requests", len(*received))
+	}
+	parts := (*received)[0].Contents[0].Parts
+	if !strings.Contains(parts[0].Text, "an at sign symbol '@'") {
+		t.Errorf("prompt does not name the challenge: %q", parts[0].Text)
+	}
+	if parts[1].InlineData == nil || parts[1].InlineData.MimeType != "image/png" {
+		t.Errorf("image part missing: %+v", parts[1])
+	}
+}
+
+func TestExcuteFencedJSON(t *testing.T) {
+	p, _ := newStub(t, http.StatusOK, candidate("```json\n{\"similarity\": 42, \"feedback\": \"ok\", \"feedback_ko\": \"괜찮아요\"}\n```"))
+
+	out, err := p.Excute(context.Background(), domain.DrawingRequest{ImageData: testImage}, "trace-2")
+	if err != nil {
+		t.Fatalf("Excute: %v", err)
+	}
+	if got := out.(domain.AnalysisResult).Similarity; got != 42 {
+		t.Fatalf("similarity = %v, want 42", got)
+	}
+}
+
+func TestExcuteBlockedOrEmpty(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"blocked prompt", `{"promptFeedback": {"blockReason": "SAFETY"}}`, "prompt blocked: SAFETY"},
+		{"no candidates", `{"candidates": []}`, "empty response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newStub(t, http.StatusOK, tt.body)
+			_, err := p.Excute(context.Background(), domain.DrawingRequest{ImageData: testImage}, "trace-3")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcuteNon200(t *testing.T) {
+	p, _ := newStub(t, http.StatusTooManyRequests, `{"error": {"code": 429, "message": "Resource exhausted", "status": "RESOURCE_EXHAUSTED"}}`)
+
+	_, err := p.Excute(context.Background(), domain.DrawingRequest{ImageData: testImage}, "trace-4")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("err = %v, want *StatusError", err)
+	}
+	if statusErr.StatusCode != http.StatusTooManyRequests || statusErr.Message != "Resource exhausted" {
+		t.Fatalf("unexpected status error: %+v", statusErr)
+	}
+}
+
+func TestExcuteUsesConfiguredPromptTemplate(t *testing.T) {
+	p, received := newPromptStub(t, http.StatusOK, candidate(`{"similarity": 50, "feedback": "ok", "feedback_ko": "좋아요"}`), "Custom: compare with {target}. Reply in JSON.")
+
+	if _, err := p.Excute(context.Background(), domain.DrawingRequest{ImageData: testImage, Prompt: "an exclamation mark symbol '!'"}, "trace-1"); err != nil {
+		t.Fatalf("Excute: %v", err)
+	}
+	if _, err := p.Excute(context.Background(), domain.DrawingRequest{ImageData: testImage}, "trace-2"); err != nil {
+		t.Fatalf("Excute: %v", err)
+	}
+
+	want := []string{
+		"Custom: compare with an exclamation mark symbol '!'. Reply in JSON.",
+		"Custom: compare with a question mark symbol '?'. Reply in JSON.",
+	}
+	for i, w := range want {
+		if got := (*received)[i].Contents[0].Parts[0].Text; got != w {
+			t.Errorf("prompt %d = %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestNewProviderRejectsPromptWithoutTarget(t *testing.T) {
+	_, err := NewProvider(config.GeminiConfig{APIKey: "test-key", Prompt: "Is this a question mark?"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err == nil {
+		t.Fatal("prompt without {target} was accepted")
+	}
+}
@@ -0,0 +1,321 @@
+package heuristic
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"log/slog"
+	"math"
+
+	"github.com/returnTesha/whois/internal/canvas"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/pkg/dataurl"
+	"github.com/returnTesha/whois/pkg/logger"
+)
+
+// 비교용 격자 크기와 점수 감쇠 계수 (격자 칸 단위 평균 거리)
+const (
+	gridSize   = 32
+	distScale  = 2.2
+	strokeRate = 0.07 // 템플릿 선 두께 (격자 크기 대비)
+
+	templateWidth = 0.6 // 템플릿 가로 폭 (격자 크기 대비)
+
+	// 격자에 맞추기 전 원본 기준 감점: 꽉 칠한 도형과 아주 작은 낙서는 늘려 놓으면 모양이 뭉개져 점수가 부풀기 쉽습니다.
+	maxInkDensity = 0.35 // 잉크 영역 안에서 잉크가 차지하는 비율. 손으로 그린 물음표는 대개 0.3 아래
+	minInkExtent  = 0.1  // 잉크 영역의 긴 변 / 캔버스의 짧은 변
+)
+
+// HeuristicProvider - 외부 호출 없이 PNG를 직접 분석해 물음표와의 유사도를 추정합니다.
+// LLM보다 거칠기 때문에 분석기가 죽었을 때의 대체(fallback) 용도로만 씁니다.
+type HeuristicProvider struct {
+	logger   *slog.Logger
+	name     string
+	template mask
+}
+
+func NewProvider(baseLogger *slog.Logger) *HeuristicProvider {
+	return &HeuristicProvider{
+		logger:   logger.WithProvider(baseLogger, "heuristic"),
+		name:     "heuristic",
+		template: questionMarkTemplate(),
+	}
+}
+
+func (p *HeuristicProvider) GetName() string {
+	return p.name
+}
+
+func (p *HeuristicProvider) Excute(ctx context.Context, data interface{}, traceID string) (interface{}, error) {
+	req, ok := data.(domain.DrawingRequest)
+	if !ok {
+		return nil, fmt.Errorf("invalid request type")
+	}
+
+	// 템플릿이 물음표 하나뿐이라 다른 도형은 점수를 낼 수 없습니다.
+	if req.Symbol != "" && req.Symbol != "?" {
+		return nil, fmt.Errorf("heuristic: unsupported symbol %q", req.Symbol)
+	}
+
+	_, raw, err := dataurl.Decode(req.ImageData)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("heuristic: decode image: %w", err)
+	}
+
+	drawing, ink, ok := normalize(img)
+	if !ok {
+		return domain.AnalysisResult{
+			Similarity: 0,
+			Feedback:   "The canvas looks empty. Draw a question mark and try again.",
+			FeedbackKo: "캔버스가 비어 있어요. 물음표를 그린 뒤 다시 시도해 주세요.",
+		}, nil
+	}
+
+	// 정규화된 그림은 잉크 영역을 꽉 채우도록 늘려져 있으므로 원래 크기는 게이트웨이가 잰 값을 씁니다.
+	if req.InkExtent > 0 {
+		ink.extent = req.InkExtent
+	}
+	score := p.score(drawing, ink)
+	p.logger.Info("Heuristic score", "traceID", traceID, "similarity", score, "inkDensity", ink.density, "inkExtent", ink.extent)
+
+	feedback, feedbackKo := describe(score)
+	return domain.AnalysisResult{
+		Similarity: score,
+		Feedback:   feedback,
+		FeedbackKo: feedbackKo,
+	}, nil
+}
+
+// score - 템플릿과의 양방향 평균 거리(chamfer)로 기본 점수를 내고, 점(dot) 유무와 원본 잉크 모양으로 보정합니다.
+func (p *HeuristicProvider) score(drawing mask, ink inkStats) float64 {
+	forward := drawing.meanDistanceTo(p.template)
+	backward := p.template.meanDistanceTo(drawing)
+	base := 100 * math.Exp(-((forward+backward)/2)/distScale)
+
+	// 물음표는 위쪽 갈고리와 아래쪽 점, 두 덩어리로 이루어집니다.
+	if !drawing.hasDotBelow() {
+		base *= 0.85
+	}
+	// 선이 아니라 면에 가까우면 (채운 사각형, 크게 늘린 점 등) 밀도가 높을수록 깎습니다. 2배 밀도면 0점
+	if ink.density > maxInkDensity {
+		base *= math.Max(0, 1-(ink.density-maxInkDensity)/maxInkDensity)
+	}
+	// 캔버스에 비해 너무 작게 그린 그림은 크기에 비례해 깎습니다.
+	if ink.extent < minInkExtent {
+		base *= ink.extent / minInkExtent
+	}
+	return math.Round(math.Max(0, math.Min(100, base))*10) / 10
+}
+
+func describe(score float64) (string, string) {
+	switch {
+	case score >= 80:
+		return "Offline estimate: this looks like a clear question mark.",
+			"오프라인 추정: 또렷한 물음표로 보여요."
+	case score >= 50:
+		return "Offline estimate: it resembles a question mark, but the curve or the dot could be clearer.",
+			"오프라인 추정: 물음표와 비슷하지만 곡선이나 점을 조금 더 또렷하게 그려 보세요."
+	default:
+		return "Offline estimate: this does not look much like a question mark yet.",
+			"오프라인 추정: 아직은 물음표와 많이 달라 보여요."
+	}
+}
+
+// mask - gridSize x gridSize 이진 격자
+type mask [gridSize][gridSize]bool
+
+// inkStats - 격자에 맞추기 전 원본 이미지에서 잰 잉크 모양
+type inkStats struct {
+	density float64 // 잉크 픽셀 수 / 잉크 영역(bounding box) 넓이
+	extent  float64 // 잉크 영역의 긴 변 / 캔버스의 짧은 변
+}
+
+// normalize - 잉크 영역만 잘라내어 격자 가운데에 맞춥니다. 잉크가 없으면 false.
+func normalize(img image.Image) (mask, inkStats, bool) {
+	var m mask
+	var stats inkStats
+	b := img.Bounds()
+	minX, minY, maxX, maxY := b.Max.X, b.Max.Y, b.Min.X-1, b.Min.Y-1
+
+	ink := func(x, y int) bool {
+		return canvas.IsInk(img.At(x, y))
+	}
+
+	pixels := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if ink(x, y) {
+				pixels++
+				minX, minY = min(minX, x), min(minY, y)
+				maxX, maxY = max(maxX, x), max(maxY, y)
+			}
+		}
+	}
+	if maxX < minX {
+		return m, stats, false
+	}
+
+	// 높이는 격자에 꽉 채우고, 가로는 템플릿 비율(0.6)로 맞춥니다.
+	// 단, 세로선처럼 아주 좁거나 가로로 긴 그림은 원래 비율을 유지해서 점수가 부풀지 않게 합니다.
+	w, h := maxX-minX+1, maxY-minY+1
+	stats.density = float64(pixels) / float64(w*h)
+	stats.extent = float64(max(w, h)) / float64(min(b.Dx(), b.Dy()))
+
+	scaleY := float64(gridSize) / float64(max(w, h))
+	scaleX := scaleY
+	if ratio := float64(w) / float64(h); ratio >= 0.35 && ratio <= 1.2 {
+		scaleY = float64(gridSize) / float64(h)
+		scaleX = templateWidth * gridSize / float64(w)
+	}
+	offX := (float64(gridSize) - float64(w)*scaleX) / 2
+	offY := (float64(gridSize) - float64(h)*scaleY) / 2
+
+	// 격자 한 칸에 해당하는 원본 영역에 잉크가 하나라도 있으면 칠합니다.
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			if ink(x, y) {
+				gx := min(int(offX+float64(x-minX)*scaleX), gridSize-1)
+				gy := min(int(offY+float64(y-minY)*scaleY), gridSize-1)
+				m[gy][gx] = true
+			}
+		}
+	}
+	return m, stats, true
+}
+
+// questionMarkTemplate - 위쪽 갈고리(원호), 세로 획, 아래쪽 점으로 물음표를 그립니다.
+func questionMarkTemplate() mask {
+	var m mask
+	radius := strokeRate * gridSize
+
+	stamp := func(cx, cy, r float64) {
+		for y := 0; y < gridSize; y++ {
+			for x := 0; x < gridSize; x++ {
+				dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+				if dx*dx+dy*dy <= r*r {
+					m[y][x] = true
+				}
+			}
+		}
+	}
+
+	// 비율은 일반적인 손글씨 물음표(세로:가로 ≈ 1.6:1)에 맞춥니다. 위아래는 격자를 꽉 채웁니다.
+	n := float64(gridSize)
+	width := templateWidth * n
+	left := (n - width) / 2
+	arcCX, arcCY, arcR := left+width/2, 0.3*n, width/2-radius
+
+	// 1) 왼쪽(180°)에서 위를 지나 오른쪽 아래(약 -50°)까지의 원호
+	for deg := 200.0; deg >= -50; deg -= 2 {
+		rad := deg * math.Pi / 180
+		stamp(arcCX+arcR*math.Cos(rad), arcCY-arcR*math.Sin(rad), radius)
+	}
+
+	// 2) 원호 끝에서 가운데로 내려오는 획
+	endRad := -50 * math.Pi / 180
+	sx, sy := arcCX+arcR*math.Cos(endRad), arcCY-arcR*math.Sin(endRad)
+	stemX, stemTop, stemBottom := arcCX, 0.58*n, 0.72*n
+	for t := 0.0; t <= 1; t += 0.05 {
+		stamp(sx+(stemX-sx)*t, sy+(stemTop-sy)*t, radius)
+	}
+	for y := stemTop; y <= stemBottom; y += 0.5 {
+		stamp(stemX, y, radius)
+	}
+
+	// 3) 점
+	stamp(stemX, 0.9*n, radius*1.3)
+	return m
+}
+
+// meanDistanceTo - 이 마스크의 잉크 칸에서 other의 가장 가까운 잉크 칸까지의 평균 거리
+func (m *mask) meanDistanceTo(other mask) float64 {
+	var points [][2]int
+	for y := 0; y < gridSize; y++ {
+		for x := 0; x < gridSize; x++ {
+			if other[y][x] {
+				points = append(points, [2]int{x, y})
+			}
+		}
+	}
+	if len(points) == 0 {
+		return gridSize
+	}
+
+	total, count := 0.0, 0
+	for y := 0; y < gridSize; y++ {
+		for x := 0; x < gridSize; x++ {
+			if !m[y][x] {
+				continue
+			}
+			best := math.MaxFloat64
+			for _, pt := range points {
+				dx, dy := float64(pt[0]-x), float64(pt[1]-y)
+				best = math.Min(best, dx*dx+dy*dy)
+			}
+			total += math.Sqrt(best)
+			count++
+		}
+	}
+	if count == 0 {
+		return gridSize
+	}
+	return total / float64(count)
+}
+
+// hasDotBelow - 가장 큰 덩어리 아래쪽에 떨어진 작은 덩어리(점)가 있는지 확인합니다.
+func (m *mask) hasDotBelow() bool {
+	var seen mask
+	type component struct {
+		size       int
+		minY, maxY int
+	}
+	var comps []component
+
+	for y := 0; y < gridSize; y++ {
+		for x := 0; x < gridSize; x++ {
+			if !m[y][x] || seen[y][x] {
+				continue
+			}
+			c := component{minY: y, maxY: y}
+			stack := [][2]int{{x, y}}
+			seen[y][x] = true
+			for len(stack) > 0 {
+				pt := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				c.size++
+				c.minY, c.maxY = min(c.minY, pt[1]), max(c.maxY, pt[1])
+				for _, d := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+					nx, ny := pt[0]+d[0], pt[1]+d[1]
+					if nx >= 0 && ny >= 0 && nx < gridSize && ny < gridSize && m[ny][nx] && !seen[ny][nx] {
+						seen[ny][nx] = true
+						stack = append(stack, [2]int{nx, ny})
+					}
+				}
+			}
+			comps = append(comps, c)
+		}
+	}
+	if len(comps) < 2 {
+		return false
+	}
+
+	largest := 0
+	for i, c := range comps {
+		if c.size > comps[largest].size {
+			largest = i
+		}
+	}
+	for i, c := range comps {
+		if i != largest && c.minY > comps[largest].maxY && c.size*4 < comps[largest].size {
+			return true
+		}
+	}
+	return false
+}
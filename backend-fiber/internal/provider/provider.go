@@ -1,6 +1,10 @@
 package provider
 
-import "context"
+import (
+	"context"
+
+	"github.com/returnTesha/whois/pkg/breaker"
+)
 
 type Provider interface {
 	GetName() string
@@ -28,3 +32,32 @@ func (r *Registry) GetProvider(name string) Provider {
 func (r *Registry) GetProviders() map[string]Provider {
 	return r.providers
 }
+
+// Health - 헬스체크/메트릭 엔드포인트로 내보내는 도구 상태
+type Health struct {
+	Name   string         `json:"name"`
+	Status string         `json:"status"` // up | degraded | down
+	Detail map[string]any `json:"detail,omitempty"`
+}
+
+// HealthReporter - 상태를 보고할 수 있는 도구만 구현합니다.
+type HealthReporter interface {
+	Health() Health
+}
+
+// BreakerHealth - 서킷 브레이커 상태를 Health로 바꿉니다. (open=down, half_open=degraded)
+func BreakerHealth(name string, stats breaker.Stats, detail map[string]any) Health {
+	status := "up"
+	switch stats.State {
+	case breaker.StateOpen:
+		status = "down"
+	case breaker.StateHalfOpen:
+		status = "degraded"
+	}
+
+	if detail == nil {
+		detail = map[string]any{}
+	}
+	detail["breaker"] = stats
+	return Health{Name: name, Status: status, Detail: detail}
+}
@@ -0,0 +1,269 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/provider"
+	"github.com/returnTesha/whois/pkg/logger"
+)
+
+// 앙상블 점수 결합 방식
+const (
+	CombineMean   = "mean"
+	CombineMedian = "median"
+	CombineMin    = "min"
+)
+
+type route struct {
+	name      string
+	providers []provider.Provider
+	percent   float64
+}
+
+// Router - 요청마다 실험군(route)을 골라 분석기를 실행합니다.
+// 설정된 route의 percent 합을 뺀 나머지 트래픽은 primary로 갑니다.
+type Router struct {
+	primary route
+	routes  []route
+	combine string
+	logger  *slog.Logger
+	roll    func() float64 // [0, 100)
+}
+
+func New(cfg config.AnalyzerConfig, providers map[string]provider.Provider, baseLogger *slog.Logger) (*Router, error) {
+	primaryName := cfg.Primary
+	if primaryName == "" {
+		primaryName = "spring"
+	}
+
+	combine := cfg.Combine
+	switch combine {
+	case "":
+		combine = CombineMean
+	case CombineMean, CombineMedian, CombineMin:
+	default:
+		return nil, fmt.Errorf("analyzer: unknown combine mode: %s", cfg.Combine)
+	}
+
+	lookup := func(names []string) ([]provider.Provider, error) {
+		if len(names) == 0 {
+			return nil, fmt.Errorf("analyzer: route has no providers")
+		}
+		list := make([]provider.Provider, 0, len(names))
+		seen := map[string]bool{}
+		for _, name := range names {
+			if seen[name] {
+				return nil, fmt.Errorf("analyzer: provider [%s] listed twice in a route", name)
+			}
+			seen[name] = true
+			p := providers[name]
+			if p == nil {
+				return nil, fmt.Errorf("analyzer provider [%s] not found in registry", name)
+			}
+			list = append(list, p)
+		}
+		return list, nil
+	}
+
+	primary, err := lookup([]string{primaryName})
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Router{
+		primary: route{name: primaryName, providers: primary},
+		combine: combine,
+		logger:  logger.WithProvider(baseLogger, "router"),
+		roll:    func() float64 { return rand.Float64() * 100 },
+	}
+
+	total := 0.0
+	for _, rc := range cfg.Routes {
+		if rc.Percent <= 0 {
+			continue
+		}
+		list, err := lookup(rc.Providers)
+		if err != nil {
+			return nil, err
+		}
+		name := rc.Name
+		if name == "" {
+			name = strings.Join(rc.Providers, "+")
+		}
+		total += rc.Percent
+		r.routes = append(r.routes, route{name: name, providers: list, percent: rc.Percent})
+	}
+	if total > 100 {
+		return nil, fmt.Errorf("analyzer: route percentages add up to %.1f (max 100)", total)
+	}
+	return r, nil
+}
+
+func (r *Router) GetName() string {
+	return r.primary.name
+}
+
+// Routes - 실험군별 트래픽 비율 (로그용)
+func (r *Router) Routes() map[string]float64 {
+	out := map[string]float64{}
+	rest := 100.0
+	for _, rt := range r.routes {
+		out[rt.name] += rt.percent
+		rest -= rt.percent
+	}
+	out[r.primary.name] += rest
+	return out
+}
+
+func (r *Router) Excute(ctx context.Context, data interface{}, traceID string) (interface{}, error) {
+	rt := r.pick()
+
+	if len(rt.providers) == 1 {
+		p := rt.providers[0]
+		resRaw, err := p.Excute(ctx, data, traceID)
+		if err != nil {
+			return nil, err
+		}
+		result, ok := resRaw.(domain.AnalysisResult)
+		if !ok {
+			return resRaw, nil
+		}
+		if result.Analyzer == "" {
+			result.Analyzer = p.GetName()
+		}
+		if len(r.routes) > 0 {
+			result.Variant = rt.name
+		}
+		return result, nil
+	}
+
+	return r.ensemble(ctx, rt, data, traceID)
+}
+
+// pick - 0~100 사이 값을 굴려 누적 비율로 실험군을 고릅니다.
+func (r *Router) pick() route {
+	if len(r.routes) == 0 {
+		return r.primary
+	}
+	n := r.roll()
+	for _, rt := range r.routes {
+		if n < rt.percent {
+			return rt
+		}
+		n -= rt.percent
+	}
+	return r.primary
+}
+
+type outcome struct {
+	name   string
+	result domain.AnalysisResult
+	err    error
+}
+
+// ensemble - 여러 분석기를 동시에 실행하고 성공한 결과만으로 점수를 결합합니다.
+// 피드백 문구는 결합 점수에 가장 가까운 분석기의 것을 씁니다.
+// 일부가 실패하면 남은 분석기만으로는 결합 방식(min 등)의 의미가 없어지므로 Fallback으로 표시해 보상에서 빼도록 합니다.
+func (r *Router) ensemble(ctx context.Context, rt route, data interface{}, traceID string) (interface{}, error) {
+	outcomes := make([]outcome, len(rt.providers))
+	var wg sync.WaitGroup
+	for i, p := range rt.providers {
+		wg.Add(1)
+		go func(i int, p provider.Provider) {
+			defer wg.Done()
+			o := outcome{name: p.GetName()}
+			resRaw, err := p.Excute(ctx, data, traceID)
+			if err == nil {
+				var ok bool
+				if o.result, ok = resRaw.(domain.AnalysisResult); !ok {
+					err = fmt.Errorf("도구 응답 타입 불일치")
+				}
+			}
+			o.err = err
+			outcomes[i] = o
+		}(i, p)
+	}
+	wg.Wait()
+
+	var succeeded []outcome
+	var failed []string
+	var errs []error
+	for _, o := range outcomes {
+		if o.err != nil {
+			r.logger.Warn("Ensemble member failed", "traceID", traceID, "analyzer", o.name, "error", o.err)
+			failed = append(failed, o.name)
+			errs = append(errs, fmt.Errorf("%s: %w", o.name, o.err))
+			continue
+		}
+		succeeded = append(succeeded, o)
+	}
+	if len(succeeded) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	scores := make([]float64, len(succeeded))
+	for i, o := range succeeded {
+		scores[i] = o.result.Similarity
+	}
+	combined := math.Round(combineScores(r.combine, scores)*10) / 10
+
+	best := succeeded[0]
+	names := make([]string, 0, len(succeeded))
+	perAnalyzer := make(map[string]float64, len(succeeded))
+	for _, o := range succeeded {
+		names = append(names, o.name)
+		perAnalyzer[o.name] = o.result.Similarity
+		if math.Abs(o.result.Similarity-combined) < math.Abs(best.result.Similarity-combined) {
+			best = o
+		}
+	}
+
+	r.logger.Info("Ensemble scored", "traceID", traceID, "variant", rt.name, "combine", r.combine, "similarity", combined, "scores", perAnalyzer, "failed", failed)
+	result := domain.AnalysisResult{
+		Similarity:      combined,
+		Feedback:        best.result.Feedback,
+		FeedbackKo:      best.result.FeedbackKo,
+		Analyzer:        "ensemble:" + r.combine,
+		Variant:         rt.name,
+		Analyzers:       names,
+		Scores:          perAnalyzer,
+		FailedAnalyzers: failed,
+	}
+	if len(failed) > 0 {
+		result.Fallback = true
+		result.FallbackReason = "ensemble partial: " + errors.Join(errs...).Error()
+	}
+	return result, nil
+}
+
+func combineScores(mode string, scores []float64) float64 {
+	sorted := append([]float64(nil), scores...)
+	sort.Float64s(sorted)
+
+	switch mode {
+	case CombineMin:
+		return sorted[0]
+	case CombineMedian:
+		mid := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			return (sorted[mid-1] + sorted[mid]) / 2
+		}
+		return sorted[mid]
+	}
+
+	sum := 0.0
+	for _, s := range sorted {
+		sum += s
+	}
+	return sum / float64(len(sorted))
+}
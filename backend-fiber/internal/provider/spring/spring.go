@@ -4,27 +4,65 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/returnTesha/whois/config"
 	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/provider"
+	"github.com/returnTesha/whois/pkg/breaker"
 	"github.com/returnTesha/whois/pkg/logger"
 )
 
+const analyzePath = "/api/spring/v1/analyze"
+
 type SpringProvider struct {
-	cfg    config.SpringConfig
-	logger *slog.Logger
-	name   string
+	cfg     config.SpringConfig
+	logger  *slog.Logger
+	name    string
+	client  *http.Client // 요청마다 만들지 않고 커넥션 풀을 공유합니다.
+	breaker *breaker.Breaker
+	retries atomic.Uint64
+}
+
+// StatusError - Spring 서버가 200이 아닌 응답을 준 경우
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("spring server returned status: %d", e.StatusCode)
 }
 
 func NewProvider(cfg config.SpringConfig, baseLogger *slog.Logger) *SpringProvider {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 60000
+	}
+	if cfg.RetryBackoffMs <= 0 {
+		cfg.RetryBackoffMs = 300
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 20
+	transport.IdleConnTimeout = 90 * time.Second
+
 	return &SpringProvider{
 		cfg:    cfg,
 		logger: logger.WithProvider(baseLogger, "spring"),
 		name:   "spring",
+		client: &http.Client{
+			Timeout:   time.Duration(cfg.Timeout) * time.Millisecond,
+			Transport: transport,
+		},
+		breaker: breaker.New(cfg.BreakerThreshold, time.Duration(cfg.BreakerOpenSec)*time.Second),
 	}
 }
 
@@ -39,40 +77,141 @@ func (p *SpringProvider) Excute(ctx context.Context, data interface{}, traceID s
 		return nil, fmt.Errorf("invalid request type")
 	}
 
+	// challenge/symbol/prompt는 도형별 분석용 (모르는 Spring 버전은 무시)
 	bodyBytes, err := json.Marshal(map[string]string{
-		"image": req.ImageData,
+		"image":     req.ImageData,
+		"challenge": req.Challenge,
+		"symbol":    req.Symbol,
+		"prompt":    req.Prompt,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("json marshal error: %w", err)
 	}
 
-	// 2. 이제 이 바이트 슬라이스를 버퍼에 담아 보냅니다.
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.cfg.BaseURL+"/api/spring/v1/analyze", bytes.NewBuffer(bodyBytes))
+	// 2. 회로가 열려 있으면 Spring을 기다리지 않고 바로 실패
+	if err := p.breaker.Allow(); err != nil {
+		p.logger.Warn("Spring circuit open, skipping call", "traceID", traceID)
+		return nil, fmt.Errorf("spring: %w", err)
+	}
+
+	var result domain.AnalysisResult
+	for attempt := 0; ; attempt++ {
+		result, err = p.call(ctx, bodyBytes, traceID)
+		if err == nil || attempt >= p.cfg.MaxRetries || !retryable(ctx, err) {
+			break
+		}
+
+		p.retries.Add(1)
+		wait := p.backoff(attempt)
+		p.logger.Warn("Spring call failed, retrying", "traceID", traceID, "attempt", attempt+1, "wait", wait, "error", err)
+		if !sleep(ctx, wait) {
+			err = ctx.Err()
+			break
+		}
+	}
+
+	if errors.Is(err, context.Canceled) {
+		// 사용자가 요청을 끊은 것이므로 Spring 상태와는 무관합니다.
+		p.breaker.Release()
+	} else {
+		p.breaker.Done(!serverFailure(err))
+	}
 	if err != nil {
 		return nil, err
 	}
+	return result, nil
+}
+
+// call - 한 번의 HTTP 요청
+func (p *SpringProvider) call(ctx context.Context, body []byte, traceID string) (domain.AnalysisResult, error) {
+	var result domain.AnalysisResult
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.cfg.BaseURL+analyzePath, bytes.NewReader(body))
+	if err != nil {
+		return result, err
+	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("X-Trace-ID", traceID)
 
-	// 4. 전송 및 응답 대기
-	client := &http.Client{Timeout: 60 * time.Second}
-	resp, err := client.Do(httpReq)
+	resp, err := p.client.Do(httpReq)
 	if err != nil {
 		p.logger.Error("Spring server connection failed", "error", err)
-		return nil, err
+		return result, err
 	}
 	defer resp.Body.Close()
 
-	// 5. 응답 코드 확인
+	// 응답 코드 확인
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spring server returned status: %d", resp.StatusCode)
+		return result, &StatusError{StatusCode: resp.StatusCode}
 	}
 
-	// 6. 결과 파싱 (domain.AnalysisResult 구조체로)
-	var result domain.AnalysisResult
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	// 결과 파싱 및 검증 (봉투 형식도 허용, "Error: ..." 응답은 점수로 쓰지 않음)
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return result, err
+	}
+	result, err = provider.DecodeResult(p.name, raw)
+	if err != nil {
+		p.logger.Warn("Spring returned an unusable analysis", "traceID", traceID, "error", err)
+		return result, err
 	}
-
 	return result, nil
 }
+
+// backoff - 지수 백오프 + equal jitter (대기 시간은 base/2 ~ base 사이)
+func (p *SpringProvider) backoff(attempt int) time.Duration {
+	base := time.Duration(p.cfg.RetryBackoffMs) * time.Millisecond << attempt
+	return base/2 + rand.N(base/2+1)
+}
+
+// retryable - 연결 실패나 일시적인 게이트웨이 오류만 재시도합니다.
+// 클라이언트 타임아웃은 Gemini가 느린 경우라 다시 보내도 같은 시간을 또 기다리게 되므로 제외합니다.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	// 응답을 받기 전 단계의 전송 오류 (connection refused, reset 등)
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !urlErr.Timeout()
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// serverFailure - 브레이커에 실패로 기록할 에러인지. 4xx는 Spring이 살아있다는 뜻이므로 제외합니다.
+func serverFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	return true
+}
+
+// Health - 서킷 브레이커 상태를 헬스체크로 보고합니다.
+func (p *SpringProvider) Health() provider.Health {
+	return provider.BreakerHealth(p.name, p.breaker.Stats(), map[string]any{
+		"retries":    p.retries.Load(),
+		"timeout_ms": p.cfg.Timeout,
+	})
+}
@@ -0,0 +1,159 @@
+package springgrpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/provider"
+	"github.com/returnTesha/whois/pkg/breaker"
+	"github.com/returnTesha/whois/pkg/dataurl"
+	"github.com/returnTesha/whois/pkg/logger"
+	analysisv1 "github.com/returnTesha/whois/proto/analysis/v1"
+)
+
+// gRPC 내장 재시도 정책 (UNAVAILABLE만, 지수 백오프 + jitter)
+const serviceConfigTemplate = `{
+  "methodConfig": [{
+    "name": [{"service": "analysis.v1.DrawingAnalysisService"}],
+    "retryPolicy": {
+      "maxAttempts": %d,
+      "initialBackoff": "%.3fs",
+      "maxBackoff": "5s",
+      "backoffMultiplier": 2,
+      "retryableStatusCodes": ["UNAVAILABLE"]
+    }
+  }]
+}`
+
+// GRPCProvider - REST SpringProvider와 같은 계약(이름 "spring", DrawingRequest → AnalysisResult)을 gRPC로 구현합니다.
+type GRPCProvider struct {
+	cfg     config.SpringConfig
+	logger  *slog.Logger
+	name    string
+	conn    *grpc.ClientConn
+	client  analysisv1.DrawingAnalysisServiceClient
+	breaker *breaker.Breaker
+}
+
+func NewProvider(cfg config.SpringConfig, baseLogger *slog.Logger) (*GRPCProvider, error) {
+	if cfg.GRPCAddr == "" {
+		return nil, fmt.Errorf("spring grpc: grpc_addr is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 60000
+	}
+	if cfg.RetryBackoffMs <= 0 {
+		cfg.RetryBackoffMs = 300
+	}
+
+	// maxAttempts는 첫 시도를 포함하며 gRPC가 2~5로 제한합니다.
+	attempts := min(max(cfg.MaxRetries+1, 2), 5)
+	serviceConfig := fmt.Sprintf(serviceConfigTemplate, attempts, float64(cfg.RetryBackoffMs)/1000)
+
+	// 클러스터 내부 통신이므로 평문 연결을 씁니다. (연결은 첫 호출 때 맺어집니다)
+	conn, err := grpc.NewClient(cfg.GRPCAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("spring grpc: %w", err)
+	}
+
+	return &GRPCProvider{
+		cfg:     cfg,
+		logger:  logger.WithProvider(baseLogger, "spring-grpc"),
+		name:    "spring",
+		conn:    conn,
+		client:  analysisv1.NewDrawingAnalysisServiceClient(conn),
+		breaker: breaker.New(cfg.BreakerThreshold, time.Duration(cfg.BreakerOpenSec)*time.Second),
+	}, nil
+}
+
+func (p *GRPCProvider) GetName() string {
+	return p.name
+}
+
+func (p *GRPCProvider) Excute(ctx context.Context, data interface{}, traceID string) (interface{}, error) {
+	req, ok := data.(domain.DrawingRequest)
+	if !ok {
+		return nil, fmt.Errorf("invalid request type")
+	}
+
+	// REST와 달리 base64 문자열이 아니라 원본 바이트를 보냅니다.
+	mime, image, err := dataurl.Decode(req.ImageData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.breaker.Allow(); err != nil {
+		p.logger.Warn("Spring circuit open, skipping call", "traceID", traceID)
+		return nil, fmt.Errorf("spring: %w", err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, time.Duration(p.cfg.Timeout)*time.Millisecond)
+	defer cancel()
+
+	resp, err := p.client.Analyze(callCtx, &analysisv1.AnalyzeRequest{
+		Image:     image,
+		MimeType:  mime,
+		TraceId:   traceID,
+		Challenge: req.Challenge,
+		Symbol:    req.Symbol,
+		Prompt:    req.Prompt,
+	})
+
+	code := status.Code(err)
+	if err != nil {
+		if code == codes.Canceled {
+			p.breaker.Release()
+		} else {
+			p.breaker.Done(!serverFailure(code))
+		}
+		p.logger.Error("Spring grpc call failed", "traceID", traceID, "code", code.String(), "error", err)
+		return nil, fmt.Errorf("spring grpc: %w", err)
+	}
+
+	// 응답은 왔어도 쓸 수 없는 결과면 REST와 같이 브레이커 실패로 셉니다.
+	result := domain.AnalysisResult{
+		Similarity: resp.GetSimilarity(),
+		Feedback:   resp.GetFeedback(),
+		FeedbackKo: resp.GetFeedbackKo(),
+	}
+	if err := provider.ValidateResult(p.name, result); err != nil {
+		p.breaker.Done(false)
+		p.logger.Warn("Spring returned an unusable analysis", "traceID", traceID, "error", err)
+		return nil, err
+	}
+	p.breaker.Done(true)
+	return result, nil
+}
+
+// serverFailure - 요청 자체가 잘못된 경우(InvalidArgument 등)는 브레이커 실패로 세지 않습니다.
+func serverFailure(code codes.Code) bool {
+	switch code {
+	case codes.OK, codes.InvalidArgument, codes.NotFound, codes.FailedPrecondition, codes.PermissionDenied, codes.Unauthenticated:
+		return false
+	}
+	return true
+}
+
+func (p *GRPCProvider) Health() provider.Health {
+	return provider.BreakerHealth(p.name, p.breaker.Stats(), map[string]any{
+		"transport":  "grpc",
+		"connection": p.conn.GetState().String(),
+		"timeout_ms": p.cfg.Timeout,
+	})
+}
+
+func (p *GRPCProvider) Close() error {
+	return p.conn.Close()
+}
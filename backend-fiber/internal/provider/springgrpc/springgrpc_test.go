@@ -0,0 +1,102 @@
+package springgrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/provider"
+	"github.com/returnTesha/whois/pkg/breaker"
+	analysisv1 "github.com/returnTesha/whois/proto/analysis/v1"
+)
+
+// 1x1 PNG
+const testImage = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg=="
+
+func TestExcuteRoundTripsChallenge(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received *analysisv1.AnalyzeRequest
+	)
+	stub, err := StartStub("127.0.0.1:0", func(ctx context.Context, req *analysisv1.AnalyzeRequest) (*analysisv1.AnalyzeResponse, error) {
+		mu.Lock()
+		received = req
+		mu.Unlock()
+		return FixedScore(91)(ctx, req)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stub.Stop()
+
+	p, err := NewProvider(config.SpringConfig{GRPCAddr: stub.Addr(), Timeout: 5000}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	out, err := p.Excute(context.Background(), domain.DrawingRequest{
+		ImageData: testImage,
+		Challenge: "at-sign",
+		Symbol:    "@",
+		Prompt:    "an at sign symbol '@'",
+	}, "trace-1")
+	if err != nil {
+		t.Fatalf("Excute: %v", err)
+	}
+
+	result := out.(domain.AnalysisResult)
+	if result.Similarity != 91 || result.Feedback == "" || result.FeedbackKo == "" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if received == nil {
+		t.Fatal("stub did not receive a request")
+	}
+	if received.GetChallenge() != "at-sign" || received.GetSymbol() != "@" || received.GetPrompt() != "an at sign symbol '@'" {
+		t.Errorf("challenge fields not forwarded: challenge=%q symbol=%q prompt=%q", received.GetChallenge(), received.GetSymbol(), received.GetPrompt())
+	}
+	if received.GetTraceId() != "trace-1" || received.GetMimeType() != "image/png" || len(received.GetImage()) == 0 {
+		t.Errorf("unexpected request: trace=%q mime=%q image=%d bytes", received.GetTraceId(), received.GetMimeType(), len(received.GetImage()))
+	}
+}
+
+// 응답이 와도 쓸 수 없는 결과(범위 밖 점수)는 브레이커 실패로 세어야 합니다.
+func TestInvalidResultsOpenBreaker(t *testing.T) {
+	var calls atomic.Int32
+	stub, err := StartStub("127.0.0.1:0", func(ctx context.Context, req *analysisv1.AnalyzeRequest) (*analysisv1.AnalyzeResponse, error) {
+		calls.Add(1)
+		return FixedScore(150)(ctx, req)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stub.Stop()
+
+	p, err := NewProvider(config.SpringConfig{GRPCAddr: stub.Addr(), Timeout: 5000, BreakerThreshold: 2, BreakerOpenSec: 60}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	req := domain.DrawingRequest{ImageData: testImage}
+	for i := 0; i < 2; i++ {
+		if _, err := p.Excute(context.Background(), req, "trace-1"); !errors.Is(err, provider.ErrInvalidResponse) {
+			t.Fatalf("call %d: err = %v, want invalid response", i, err)
+		}
+	}
+	if _, err := p.Excute(context.Background(), req, "trace-1"); !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("err = %v, want open breaker", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("stub received %d calls, want 2", n)
+	}
+}
@@ -0,0 +1,68 @@
+package springgrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	analysisv1 "github.com/returnTesha/whois/proto/analysis/v1"
+)
+
+// AnalyzeFunc - 스텁 서버가 돌려줄 응답을 만드는 함수
+type AnalyzeFunc func(ctx context.Context, req *analysisv1.AnalyzeRequest) (*analysisv1.AnalyzeResponse, error)
+
+// StubServer - Spring 대신 같은 프로세스 안에서 띄우는 분석 서버 (로컬 개발/검증용)
+type StubServer struct {
+	analysisv1.UnimplementedDrawingAnalysisServiceServer
+
+	analyze AnalyzeFunc
+	server  *grpc.Server
+	lis     net.Listener
+}
+
+// FixedScore - 항상 같은 점수를 돌려주는 AnalyzeFunc
+func FixedScore(similarity float64) AnalyzeFunc {
+	return func(ctx context.Context, req *analysisv1.AnalyzeRequest) (*analysisv1.AnalyzeResponse, error) {
+		return &analysisv1.AnalyzeResponse{
+			Similarity: similarity,
+			Feedback:   fmt.Sprintf("Stub analysis of %d bytes (%s).", len(req.GetImage()), req.GetMimeType()),
+			FeedbackKo: fmt.Sprintf("스텁 서버가 %d바이트 이미지를 분석했어요.", len(req.GetImage())),
+		}, nil
+	}
+}
+
+// StartStub - addr(예: "127.0.0.1:0")에서 스텁 서버를 띄웁니다. 실제 주소는 Addr()로 확인합니다.
+func StartStub(addr string, analyze AnalyzeFunc) (*StubServer, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StubServer{
+		analyze: analyze,
+		server:  grpc.NewServer(),
+		lis:     lis,
+	}
+	analysisv1.RegisterDrawingAnalysisServiceServer(s.server, s)
+	go s.server.Serve(lis)
+	return s, nil
+}
+
+func (s *StubServer) Addr() string {
+	return s.lis.Addr().String()
+}
+
+func (s *StubServer) Stop() {
+	s.server.GracefulStop()
+}
+
+func (s *StubServer) Analyze(ctx context.Context, req *analysisv1.AnalyzeRequest) (*analysisv1.AnalyzeResponse, error) {
+	if len(req.GetImage()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "image is empty")
+	}
+	return s.analyze(ctx, req)
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+// ErrInvalidResponse - 분석기가 응답은 했지만 점수로 쓸 수 없는 내용인 경우 (errors.Is로 확인)
+var ErrInvalidResponse = errors.New("invalid analyzer response")
+
+// ResponseError - 어떤 분석기의 응답이 왜 거부됐는지
+type ResponseError struct {
+	Provider string
+	Reason   string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Provider, ErrInvalidResponse, e.Reason)
+}
+
+func (e *ResponseError) Is(target error) bool {
+	return target == ErrInvalidResponse
+}
+
+// Spring은 Gemini 호출이 실패하면 200과 함께 similarity 0, "Error: ..." 피드백을 돌려줍니다.
+var errorPrefixes = []string{"error:", "오류:", "exception:"}
+
+// ValidateResult - 점수 범위와 영/한 피드백을 확인하고, 에러 문구를 담은 응답을 걸러냅니다.
+func ValidateResult(name string, r domain.AnalysisResult) error {
+	if math.IsNaN(r.Similarity) || math.IsInf(r.Similarity, 0) || r.Similarity < 0 || r.Similarity > 100 {
+		return &ResponseError{Provider: name, Reason: fmt.Sprintf("similarity out of range: %v", r.Similarity)}
+	}
+
+	for _, text := range []string{r.Feedback, r.FeedbackKo} {
+		lower := strings.ToLower(strings.TrimSpace(text))
+		for _, prefix := range errorPrefixes {
+			if strings.HasPrefix(lower, prefix) {
+				return &ResponseError{Provider: name, Reason: "error payload: " + strings.TrimSpace(text)}
+			}
+		}
+	}
+
+	if strings.TrimSpace(r.Feedback) == "" {
+		return &ResponseError{Provider: name, Reason: "empty feedback"}
+	}
+	if strings.TrimSpace(r.FeedbackKo) == "" {
+		return &ResponseError{Provider: name, Reason: "empty feedback_ko"}
+	}
+	return nil
+}
+
+// DecodeResult - 분석 결과 JSON을 읽고 검증합니다.
+// 결과 객체를 그대로 주는 경우와 SpringAIResponse 봉투({"status", "data"})로 감싼 경우를 모두 받습니다.
+func DecodeResult(name string, raw []byte) (domain.AnalysisResult, error) {
+	var result domain.AnalysisResult
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return result, &ResponseError{Provider: name, Reason: "malformed JSON: " + err.Error()}
+	}
+
+	_, hasData := fields["data"]
+	_, hasStatus := fields["status"]
+	if hasData || hasStatus {
+		var envelope struct {
+			domain.SpringAIResponse
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			return result, &ResponseError{Provider: name, Reason: "malformed envelope: " + err.Error()}
+		}
+		switch strings.ToLower(envelope.Status) {
+		case "", "ok", "success":
+		default:
+			reason := "envelope status: " + envelope.Status
+			if envelope.Message != "" {
+				reason += " (" + envelope.Message + ")"
+			}
+			return result, &ResponseError{Provider: name, Reason: reason}
+		}
+		if !hasData {
+			return result, &ResponseError{Provider: name, Reason: "envelope without data"}
+		}
+		var dataFields map[string]json.RawMessage
+		if err := json.Unmarshal(fields["data"], &dataFields); err != nil {
+			return result, &ResponseError{Provider: name, Reason: "malformed envelope data: " + err.Error()}
+		}
+		fields = dataFields
+		result = envelope.Data
+	} else if err := json.Unmarshal(raw, &result); err != nil {
+		return result, &ResponseError{Provider: name, Reason: "unexpected JSON shape: " + err.Error()}
+	}
+
+	// 숫자 0과 필드 누락을 구분합니다.
+	if _, ok := fields["similarity"]; !ok {
+		return result, &ResponseError{Provider: name, Reason: "missing similarity"}
+	}
+	return result, ValidateResult(name, result)
+}
@@ -0,0 +1,205 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/clientip"
+	"github.com/returnTesha/whois/internal/privacy"
+	"github.com/returnTesha/whois/internal/siwe"
+)
+
+// 제한 규칙 이름
+const (
+	RuleAnalysis = "analysis"
+	RuleReward   = "reward"
+)
+
+// DecisionLimited - 보상 자격 제한에 걸렸을 때의 보상 판정 (보상 정책의 allow/downgrade/block과 같은 자리에 씁니다)
+const DecisionLimited = "rate_limited"
+
+const defaultFlushInterval = 30 * time.Second
+
+// Identity - 요청을 구분하는 값. 비어 있는 값은 제한 키에서 빠집니다.
+type Identity struct {
+	IP      string
+	Session string
+	Wallet  string
+}
+
+// FromCtx - 확인된 클라이언트 IP, 지갑 세션, 지갑 주소
+func FromCtx(c *fiber.Ctx) Identity {
+	return Identity{
+		IP:      clientip.FromCtx(c),
+		Session: siwe.SessionIDFromCtx(c),
+		Wallet:  siwe.FromCtx(c),
+	}
+}
+
+type rule struct {
+	limit Limit
+	keys  []string
+}
+
+// Limiter - 규칙별 토큰 버킷. 한 요청은 IP, 세션, 지갑 버킷 모두에 토큰이 있어야 통과합니다.
+type Limiter struct {
+	store         Store
+	anonymizer    *privacy.Anonymizer // 버킷 키(파일에 저장됨)의 IP를 ip_mode대로 가공합니다
+	rules         map[string]rule
+	flushInterval time.Duration
+	logger        *slog.Logger
+}
+
+func New(cfg config.RateLimitConfig, anonymizer *privacy.Anonymizer, logger *slog.Logger) (*Limiter, error) {
+	logger = logger.With("layer", "ratelimit")
+
+	var store Store
+	switch cfg.Store {
+	case "", "memory":
+		store = NewMemoryStore()
+	case "file":
+		fileStore, err := NewFileStore(cfg.File, logger)
+		if err != nil {
+			return nil, err
+		}
+		store = fileStore
+	default:
+		return nil, fmt.Errorf("ratelimit: unknown store %q", cfg.Store)
+	}
+
+	l := &Limiter{
+		store:         store,
+		anonymizer:    anonymizer,
+		rules:         map[string]rule{},
+		flushInterval: time.Duration(cfg.FlushSec) * time.Second,
+		logger:        logger,
+	}
+	if l.flushInterval <= 0 {
+		l.flushInterval = defaultFlushInterval
+	}
+	for name, rc := range map[string]config.RateRuleConfig{RuleAnalysis: cfg.Analysis, RuleReward: cfg.Reward} {
+		r, err := newRule(rc)
+		if err != nil {
+			return nil, fmt.Errorf("ratelimit: %s: %w", name, err)
+		}
+		if r != nil {
+			l.rules[name] = *r
+		}
+	}
+	return l, nil
+}
+
+func newRule(cfg config.RateRuleConfig) (*rule, error) {
+	if cfg.Limit <= 0 {
+		return nil, nil
+	}
+	if cfg.PeriodSec <= 0 {
+		return nil, fmt.Errorf("period_sec must be positive")
+	}
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = cfg.Limit
+	}
+	keys := cfg.Keys
+	if len(keys) == 0 {
+		keys = []string{"ip", "session", "wallet"}
+	}
+	for _, k := range keys {
+		if k != "ip" && k != "session" && k != "wallet" {
+			return nil, fmt.Errorf("unknown key %q", k)
+		}
+	}
+	return &rule{
+		limit: Limit{Rate: float64(cfg.Limit) / float64(cfg.PeriodSec), Burst: float64(burst)},
+		keys:  keys,
+	}, nil
+}
+
+// Start - 다 찬 버킷을 정리하고 상태를 저장합니다. ctx가 끝나면 마지막으로 한 번 더 저장합니다.
+func (l *Limiter) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(l.flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				l.flush()
+				return
+			case <-ticker.C:
+				l.store.Sweep(time.Now())
+				l.flush()
+			}
+		}
+	}()
+}
+
+// Allow - 규칙이 없으면 항상 통과. 막히면 다시 시도할 수 있을 때까지의 시간을 돌려줍니다.
+func (l *Limiter) Allow(name string, id Identity) (bool, time.Duration) {
+	r, ok := l.rules[name]
+	if !ok {
+		return true, 0
+	}
+	var keys []string
+	for _, k := range r.keys {
+		var v string
+		switch k {
+		case "ip":
+			v = l.ip(id.IP)
+		case "session":
+			v = id.Session
+		case "wallet":
+			v = id.Wallet
+		}
+		if v != "" {
+			keys = append(keys, name+":"+k+":"+v)
+		}
+	}
+	if len(keys) == 0 {
+		return true, 0
+	}
+	return l.store.Take(keys, r.limit, time.Now())
+}
+
+// Middleware - 제한에 걸리면 Retry-After와 함께 429로 돌려보냅니다.
+func (l *Limiter) Middleware(name string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := FromCtx(c)
+		ok, wait := l.Allow(name, id)
+		if ok {
+			return c.Next()
+		}
+		retryAfter := RetryAfterSeconds(wait)
+		l.logger.Warn("요청 제한", "rule", name, "ip", l.ip(id.IP), "wallet", id.Wallet, "retryAfter", retryAfter)
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error":       "Too many requests",
+			"retry_after": retryAfter,
+		})
+	}
+}
+
+// RetryAfterSeconds - Retry-After 헤더 값 (올림, 최소 1초)
+func RetryAfterSeconds(wait time.Duration) int {
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
+
+// ip - 원래 IP가 상태 파일에 남지 않도록 히스토리와 같은 방식(hash/truncate)으로 가공합니다.
+func (l *Limiter) ip(raw string) string {
+	if l.anonymizer == nil || raw == "" {
+		return raw
+	}
+	return l.anonymizer.IP(raw)
+}
+
+func (l *Limiter) flush() {
+	if err := l.store.Flush(); err != nil {
+		l.logger.Error("제한 상태 저장 실패", "error", err)
+	}
+}
@@ -0,0 +1,159 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxBuckets - 키를 바꿔가며 메모리를 채우는 공격에 대비한 상한. 넘치면 새 키는 막습니다.
+const maxBuckets = 200000
+
+// Limit - 초당 Rate개씩 채워지고 최대 Burst개까지 쌓이는 버킷
+type Limit struct {
+	Rate  float64
+	Burst float64
+}
+
+// Store - 버킷 상태 저장소
+type Store interface {
+	// Take - 모든 키에 토큰이 있을 때만 하나씩 꺼냅니다. 막히면 다시 시도할 수 있을 때까지의 시간을 돌려줍니다.
+	Take(keys []string, limit Limit, now time.Time) (bool, time.Duration)
+	// Sweep - 가득 찬(오래 안 쓴) 버킷을 지웁니다.
+	Sweep(now time.Time)
+	// Flush - 상태를 영구 저장소에 씁니다. (메모리 저장소는 아무 일도 하지 않음)
+	Flush() error
+}
+
+type bucket struct {
+	Tokens  float64   `json:"tokens"`
+	Updated time.Time `json:"updated"`
+	FullAt  time.Time `json:"full_at"` // 이 시각 이후엔 가득 찬 버킷과 같으므로 지워도 됩니다
+}
+
+// MemoryStore - 프로세스 메모리의 버킷. 재시작하면 초기화됩니다.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+func (s *MemoryStore) Take(keys []string, limit Limit, now time.Time) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 먼저 모든 키를 채워보고, 하나라도 모자라면 아무것도 꺼내지 않습니다.
+	current := make([]*bucket, len(keys))
+	var wait time.Duration
+	for i, key := range keys {
+		b, ok := s.buckets[key]
+		if !ok {
+			if len(s.buckets) >= maxBuckets {
+				s.sweep(now)
+			}
+			if len(s.buckets) >= maxBuckets {
+				return false, time.Second
+			}
+			b = &bucket{Tokens: limit.Burst, Updated: now}
+		}
+		refill(b, limit, now)
+		if b.Tokens < 1 {
+			wait = max(wait, time.Duration((1-b.Tokens)/limit.Rate*float64(time.Second)))
+		}
+		current[i] = b
+	}
+	if wait > 0 {
+		return false, wait
+	}
+
+	for i, key := range keys {
+		b := current[i]
+		b.Tokens--
+		b.FullAt = now.Add(time.Duration((limit.Burst - b.Tokens) / limit.Rate * float64(time.Second)))
+		s.buckets[key] = b
+	}
+	return true, 0
+}
+
+func (s *MemoryStore) Sweep(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+}
+
+func (s *MemoryStore) Flush() error {
+	return nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.FullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func refill(b *bucket, limit Limit, now time.Time) {
+	if elapsed := now.Sub(b.Updated).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(limit.Burst, b.Tokens+elapsed*limit.Rate)
+		b.Updated = now
+	}
+}
+
+// FileStore - 메모리 버킷을 주기적으로 JSON 파일에 저장해 재시작 후에도 제한을 이어갑니다.
+type FileStore struct {
+	*MemoryStore
+	path   string
+	logger *slog.Logger
+}
+
+// NewFileStore - 저장된 상태가 있으면 읽어옵니다. 파일이 깨졌으면 비운 채로 시작합니다.
+func NewFileStore(path string, logger *slog.Logger) (*FileStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("ratelimit: file is required for the file store")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("ratelimit: %w", err)
+	}
+	s := &FileStore{MemoryStore: NewMemoryStore(), path: path, logger: logger}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.buckets); err != nil {
+		logger.Warn("제한 상태 파일을 읽을 수 없어 비운 채로 시작합니다", "path", path, "error", err)
+		s.buckets = map[string]*bucket{}
+	}
+	s.Sweep(time.Now())
+	logger.Info("제한 상태 복원", "path", path, "buckets", len(s.buckets))
+	return s, nil
+}
+
+// Flush - 임시 파일에 쓴 뒤 바꿔치기합니다.
+func (s *FileStore) Flush() error {
+	s.mu.Lock()
+	data, err := json.Marshal(s.buckets)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
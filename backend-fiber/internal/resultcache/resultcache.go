@@ -0,0 +1,278 @@
+package resultcache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+const (
+	defaultTTL            = 24 * time.Hour
+	defaultMaxEntries     = 10000
+	defaultMaxDiskEntries = 100000
+	defaultSweepInterval  = 30 * time.Minute
+	staleTemp             = time.Hour // 이보다 오래된 임시 파일은 실패한 저장으로 봅니다.
+)
+
+type entry struct {
+	Hash     string                `json:"hash"`
+	Result   domain.AnalysisResult `json:"result"`
+	StoredAt time.Time             `json:"stored_at"`
+}
+
+// Cache - 정규화된 그림 해시 → 분석 결과. 메모리는 LRU로 크기를 제한하고, dir이 있으면 디스크에도 남겨 재시작 후에도 씁니다.
+type Cache struct {
+	ttl            time.Duration
+	maxEntries     int
+	dir            string
+	maxDiskEntries int
+	sweepInterval  time.Duration
+	logger         *slog.Logger
+
+	mu      sync.Mutex
+	order   *list.List // 앞쪽이 최근 사용
+	entries map[string]*list.Element
+}
+
+func New(cfg config.CacheConfig, logger *slog.Logger) *Cache {
+	ttl := time.Duration(cfg.TTLMinutes) * time.Minute
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	maxEntries := cfg.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+	maxDiskEntries := cfg.MaxDiskEntries
+	if maxDiskEntries <= 0 {
+		maxDiskEntries = defaultMaxDiskEntries
+	}
+	sweepInterval := time.Duration(cfg.SweepMinutes) * time.Minute
+	if sweepInterval <= 0 {
+		sweepInterval = defaultSweepInterval
+	}
+
+	c := &Cache{
+		ttl:            ttl,
+		maxEntries:     maxEntries,
+		dir:            cfg.Dir,
+		maxDiskEntries: maxDiskEntries,
+		sweepInterval:  sweepInterval,
+		logger:         logger.With("layer", "cache"),
+		order:          list.New(),
+		entries:        map[string]*list.Element{},
+	}
+	if c.dir != "" {
+		if err := os.MkdirAll(c.dir, 0755); err != nil {
+			c.logger.Error("캐시 디렉토리 생성 실패", "path", c.dir, "error", err)
+			c.dir = ""
+		}
+	}
+	return c
+}
+
+// Start - 디스크 캐시 정리 루프. 시작 직후 한 번 바로 정리합니다. (디스크를 쓰지 않으면 아무것도 하지 않음)
+func (c *Cache) Start(ctx context.Context) {
+	if c.dir == "" {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(c.sweepInterval)
+		defer ticker.Stop()
+		for {
+			c.Sweep()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Sweep - 만료된 파일과 남은 임시 파일을 지우고, 개수 한도를 넘으면 오래된 파일부터 지웁니다. 남은 파일 수를 돌려줍니다.
+// 저장할 때마다 새 파일을 쓰므로 파일 수정 시각을 저장 시각으로 봅니다.
+func (c *Cache) Sweep() int {
+	if c.dir == "" {
+		return 0
+	}
+
+	type file struct {
+		path string
+		at   time.Time
+	}
+	var files []file
+	expired := 0
+	cutoff := time.Now().Add(-c.ttl)
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		switch {
+		case strings.HasSuffix(path, ".tmp"):
+			// 저장 중일 수 있으므로 충분히 오래된 임시 파일만 지웁니다.
+			if info.ModTime().Before(time.Now().Add(-staleTemp)) {
+				os.Remove(path)
+			}
+		case !strings.HasSuffix(path, ".json"):
+		case info.ModTime().Before(cutoff):
+			if os.Remove(path) == nil {
+				expired++
+			}
+		default:
+			files = append(files, file{path: path, at: info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		c.logger.Error("캐시 정리 실패", "error", err)
+	}
+
+	evicted := 0
+	if len(files) > c.maxDiskEntries {
+		sort.Slice(files, func(i, j int) bool { return files[i].at.Before(files[j].at) })
+		for _, f := range files[:len(files)-c.maxDiskEntries] {
+			if os.Remove(f.path) == nil {
+				evicted++
+			}
+		}
+		files = files[len(files)-c.maxDiskEntries:]
+	}
+
+	if expired+evicted > 0 {
+		c.logger.Info("캐시 정리 완료", "expired", expired, "evicted", evicted, "remaining", len(files))
+	}
+	return len(files)
+}
+
+// Get - 유효한 캐시 결과가 있으면 돌려줍니다.
+func (c *Cache) Get(hash string) (domain.AnalysisResult, bool) {
+	if hash == "" {
+		return domain.AnalysisResult{}, false
+	}
+
+	c.mu.Lock()
+	if el, ok := c.entries[hash]; ok {
+		e := el.Value.(*entry)
+		if time.Since(e.StoredAt) < c.ttl {
+			c.order.MoveToFront(el)
+			c.mu.Unlock()
+			return e.Result, true
+		}
+		c.order.Remove(el)
+		delete(c.entries, hash)
+	}
+	c.mu.Unlock()
+
+	e, ok := c.load(hash)
+	if !ok {
+		return domain.AnalysisResult{}, false
+	}
+	c.remember(e)
+	return e.Result, true
+}
+
+// Put - 결과를 저장합니다.
+func (c *Cache) Put(hash string, result domain.AnalysisResult) {
+	if hash == "" {
+		return
+	}
+	e := &entry{Hash: hash, Result: result, StoredAt: time.Now()}
+	c.remember(e)
+	c.save(e)
+}
+
+// Len - 메모리에 올라와 있는 항목 수
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *Cache) remember(e *entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[e.Hash]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[e.Hash] = c.order.PushFront(e)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).Hash)
+	}
+}
+
+func (c *Cache) path(hash string) string {
+	return filepath.Join(c.dir, hash[:2], hash+".json")
+}
+
+// load - 디스크 캐시. 만료된 파일은 읽으면서 지웁니다.
+func (c *Cache) load(hash string) (*entry, bool) {
+	if c.dir == "" || len(hash) < 2 {
+		return nil, false
+	}
+	path := c.path(hash)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Hash != hash || time.Since(e.StoredAt) >= c.ttl {
+		os.Remove(path)
+		return nil, false
+	}
+	return &e, true
+}
+
+func (c *Cache) save(e *entry) {
+	if c.dir == "" || len(e.Hash) < 2 {
+		return
+	}
+	path := c.path(e.Hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		c.logger.Error("캐시 저장 실패", "error", err)
+		return
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	// 같은 그림이 동시에 들어와도 파일이 섞이지 않도록 임시 파일에 쓰고 rename 합니다.
+	tmp, err := os.CreateTemp(filepath.Dir(path), e.Hash+".*.tmp")
+	if err != nil {
+		c.logger.Error("캐시 저장 실패", "error", err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		c.logger.Error("캐시 저장 실패", "error", err)
+	}
+}
@@ -0,0 +1,81 @@
+package resultcache
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+func newTestCache(t *testing.T, maxDiskEntries int) *Cache {
+	t.Helper()
+	return New(config.CacheConfig{
+		TTLMinutes:     60,
+		MaxEntries:     10,
+		Dir:            t.TempDir(),
+		MaxDiskEntries: maxDiskEntries,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// age - 디스크에 저장된 항목의 저장 시각(파일 수정 시각)을 과거로 돌립니다.
+func age(t *testing.T, c *Cache, hash string, by time.Duration) {
+	t.Helper()
+	at := time.Now().Add(-by)
+	if err := os.Chtimes(c.path(hash), at, at); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(c *Cache, hash string) bool {
+	_, err := os.Stat(c.path(hash))
+	return err == nil
+}
+
+func TestSweepRemovesExpiredEntries(t *testing.T) {
+	c := newTestCache(t, 100)
+	c.Put("aa01", domain.AnalysisResult{Similarity: 10})
+	c.Put("bb02", domain.AnalysisResult{Similarity: 20})
+	age(t, c, "aa01", 2*time.Hour)
+
+	stale := filepath.Join(c.dir, "bb", "bb03.123.tmp")
+	if err := os.WriteFile(stale, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-2 * staleTemp)
+	os.Chtimes(stale, past, past)
+
+	if remaining := c.Sweep(); remaining != 1 {
+		t.Fatalf("remaining = %d, want 1", remaining)
+	}
+	if exists(c, "aa01") || !exists(c, "bb02") {
+		t.Fatal("sweep removed the wrong entry")
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatal("stale temp file was not removed")
+	}
+}
+
+func TestSweepCapsDiskEntries(t *testing.T) {
+	c := newTestCache(t, 3)
+	for i := 0; i < 5; i++ {
+		hash := fmt.Sprintf("%02x%02x", i, i)
+		c.Put(hash, domain.AnalysisResult{Similarity: float64(i)})
+		age(t, c, hash, time.Duration(5-i)*time.Minute) // 0번이 가장 오래됨
+	}
+
+	if remaining := c.Sweep(); remaining != 3 {
+		t.Fatalf("remaining = %d, want 3", remaining)
+	}
+	for i := 0; i < 5; i++ {
+		hash := fmt.Sprintf("%02x%02x", i, i)
+		if want := i >= 2; exists(c, hash) != want {
+			t.Errorf("%s exists = %v, want %v", hash, !want, want)
+		}
+	}
+}
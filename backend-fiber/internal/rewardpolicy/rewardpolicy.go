@@ -0,0 +1,233 @@
+package rewardpolicy
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/pkg/phash"
+)
+
+// 보상 판정 결과
+const (
+	ActionAllow     = "allow"
+	ActionDowngrade = "downgrade"
+	ActionBlock     = "block"
+)
+
+const (
+	defaultWindow            = 7 * 24 * time.Hour
+	defaultRecipientDistance = 6
+	defaultGlobalDistance    = 3
+	defaultDowngradeRate     = 0.1
+	defaultMaxEntries        = 50000
+)
+
+// Decision - 보상 정책 판정
+type Decision struct {
+	Action      string  `json:"action"`
+	Multiplier  float64 `json:"multiplier"`             // 지급 비율 (allow=1, block=0)
+	Scope       string  `json:"scope,omitempty"`        // 어느 기준에 걸렸는지: recipient | global
+	DuplicateOf string  `json:"duplicate_of,omitempty"` // 닮은 이전 제출의 traceID
+	Distance    int     `json:"distance,omitempty"`
+}
+
+type entry struct {
+	recipient string
+	traceID   string
+	hash      phash.Hash
+	at        time.Time
+	pending   bool // 판정은 났지만 아직 지급이 확인되지 않음 (Commit/Release 전)
+}
+
+// Policy - 최근에 보상받은 그림의 지각 해시를 기억해두고, 거의 같은 그림으로 다시 보상받는 것을 막습니다.
+// 같은 수령인의 재제출(recipient)과 남의 그림을 베낀 경우(global)를 각각 다른 거리/조치로 봅니다.
+type Policy struct {
+	cfg    config.RewardPolicyConfig
+	window time.Duration
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	entries []entry // 시간 순
+}
+
+func New(cfg config.RewardPolicyConfig, logger *slog.Logger) *Policy {
+	window := time.Duration(cfg.WindowHours) * time.Hour
+	if window <= 0 {
+		window = defaultWindow
+	}
+	if cfg.RecipientDistance <= 0 {
+		cfg.RecipientDistance = defaultRecipientDistance
+	}
+	if cfg.GlobalDistance <= 0 {
+		cfg.GlobalDistance = defaultGlobalDistance
+	}
+	if cfg.RecipientAction == "" {
+		cfg.RecipientAction = ActionBlock
+	}
+	if cfg.GlobalAction == "" {
+		cfg.GlobalAction = ActionDowngrade
+	}
+	if cfg.DowngradeRate <= 0 || cfg.DowngradeRate >= 1 {
+		cfg.DowngradeRate = defaultDowngradeRate
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = defaultMaxEntries
+	}
+
+	return &Policy{
+		cfg:    cfg,
+		window: window,
+		logger: logger.With("layer", "reward-policy"),
+	}
+}
+
+// Recipient - 수령인 구분 키. 로그인으로 확인된 지갑이 있으면 지갑, 없으면 (가공된) IP
+func Recipient(ip, verifiedWallet string) string {
+	if verifiedWallet != "" {
+		return verifiedWallet
+	}
+	return ip
+}
+
+// Paid - 보상이 지급되는 판정인지 (allow | downgrade)
+func Paid(action string) bool {
+	return action == ActionAllow || action == ActionDowngrade
+}
+
+// Load - 재시작 후에도 판정이 이어지도록 히스토리에서 최근 보상 기록으로 색인을 다시 만듭니다.
+func (p *Policy) Load(records []domain.AnalysisHistory) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cutoff := time.Now().Add(-p.window)
+	for _, r := range records {
+		// 실제로 보상한 판정만 색인에 넣습니다. (block, 요청 제한/작업 증명으로 건너뛴 판정은 지급되지 않았음)
+		// 지급 판정이어도 전송에 실패했거나 전송하지 않았으면 트랜잭션이 없으므로 넣지 않습니다.
+		if r.PHash == "" || !Paid(r.RewardDecision) || r.TxId == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, r.Timestamp)
+		if err != nil || at.Before(cutoff) {
+			continue
+		}
+		hash, err := phash.Parse(r.PHash)
+		if err != nil {
+			continue
+		}
+		verified := ""
+		if r.WalletVerified {
+			verified = r.Wallet
+		}
+		p.entries = append(p.entries, entry{recipient: Recipient(r.IP, verified), traceID: r.TraceID, hash: hash, at: at})
+	}
+	p.trim(time.Now())
+	p.logger.Info("보상 색인 복원", "entries", len(p.entries))
+}
+
+// Evaluate - 보상 직전에 호출합니다. 막히지 않은 제출은 지급 대기 상태로 색인에 추가되어,
+// 전송하는 동안 들어온 닮은 제출도 비교 대상이 됩니다. 전송에 성공하면 Commit, 아니면 Release를 불러야 합니다.
+func (p *Policy) Evaluate(recipient, traceID string, hash phash.Hash) Decision {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.trim(now)
+
+	decision := Decision{Action: ActionAllow, Multiplier: 1}
+	for i := len(p.entries) - 1; i >= 0; i-- {
+		e := p.entries[i]
+		distance := phash.Distance(e.hash, hash)
+
+		scope, action := "", ""
+		switch {
+		case recipient != "" && e.recipient == recipient && distance <= p.cfg.RecipientDistance:
+			scope, action = "recipient", p.cfg.RecipientAction
+		case distance <= p.cfg.GlobalDistance:
+			scope, action = "global", p.cfg.GlobalAction
+		default:
+			continue
+		}
+
+		// 여러 개에 걸리면 가장 엄한 조치를 씁니다.
+		if severity(action) > severity(decision.Action) {
+			decision = Decision{
+				Action:      action,
+				Multiplier:  p.multiplier(action),
+				Scope:       scope,
+				DuplicateOf: e.traceID,
+				Distance:    distance,
+			}
+		}
+		if decision.Action == ActionBlock {
+			break
+		}
+	}
+
+	if decision.Action != ActionBlock {
+		p.entries = append(p.entries, entry{recipient: recipient, traceID: traceID, hash: hash, at: now, pending: true})
+	}
+	if decision.Action != ActionAllow {
+		p.logger.Warn("유사 그림 보상 제한", "traceID", traceID, "action", decision.Action, "scope", decision.Scope, "duplicateOf", decision.DuplicateOf, "distance", decision.Distance)
+	}
+	return decision
+}
+
+// Commit - 보상 전송이 성공한 제출을 지급 완료로 확정합니다.
+func (p *Policy) Commit(traceID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := len(p.entries) - 1; i >= 0; i-- {
+		if p.entries[i].traceID == traceID && p.entries[i].pending {
+			p.entries[i].pending = false
+			return
+		}
+	}
+}
+
+// Release - 보상을 보내지 못한 제출을 색인에서 뺍니다. (같은 그림을 다시 내도 불이익이 없도록)
+func (p *Policy) Release(traceID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := len(p.entries) - 1; i >= 0; i-- {
+		if p.entries[i].traceID == traceID && p.entries[i].pending {
+			p.entries = append(p.entries[:i], p.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+func (p *Policy) multiplier(action string) float64 {
+	switch action {
+	case ActionBlock:
+		return 0
+	case ActionDowngrade:
+		return p.cfg.DowngradeRate
+	}
+	return 1
+}
+
+func severity(action string) int {
+	switch action {
+	case ActionBlock:
+		return 2
+	case ActionDowngrade:
+		return 1
+	}
+	return 0
+}
+
+// trim - 기간이 지났거나 개수 한도를 넘은 오래된 항목을 버립니다.
+func (p *Policy) trim(now time.Time) {
+	cutoff := now.Add(-p.window)
+	drop := 0
+	for drop < len(p.entries) && p.entries[drop].at.Before(cutoff) {
+		drop++
+	}
+	drop = max(drop, len(p.entries)-p.cfg.MaxEntries)
+	if drop > 0 {
+		p.entries = append(p.entries[:0], p.entries[drop:]...)
+	}
+}
@@ -0,0 +1,138 @@
+package siwe
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const headerSuffix = " wants you to sign in with your Ethereum account:"
+
+// Message - EIP-4361 메시지 (지갑이 서명한 원문을 그대로 파싱합니다)
+type Message struct {
+	Scheme         string
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// ParseMessage - EIP-4361 형식을 확인하며 읽습니다. 필드 순서도 표준과 같아야 합니다.
+func ParseMessage(raw string) (*Message, error) {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	if len(lines) < 4 {
+		return nil, fmt.Errorf("siwe: message too short")
+	}
+
+	m := &Message{}
+	header, ok := strings.CutSuffix(lines[0], headerSuffix)
+	if !ok || header == "" {
+		return nil, fmt.Errorf("siwe: invalid header line")
+	}
+	if scheme, domain, found := strings.Cut(header, "://"); found {
+		m.Scheme, m.Domain = scheme, domain
+	} else {
+		m.Domain = header
+	}
+
+	// 주소는 EIP-55 체크섬 형식이어야 합니다.
+	if !common.IsHexAddress(lines[1]) || common.HexToAddress(lines[1]).Hex() != lines[1] {
+		return nil, fmt.Errorf("siwe: address must be an EIP-55 checksummed address")
+	}
+	m.Address = common.HexToAddress(lines[1])
+
+	// 빈 줄, [statement, 빈 줄] 다음에 URI가 옵니다.
+	i := 2
+	if lines[i] != "" {
+		return nil, fmt.Errorf("siwe: expected empty line after address")
+	}
+	i++
+	if i < len(lines) && lines[i] != "" && !strings.HasPrefix(lines[i], "URI: ") {
+		m.Statement = lines[i]
+		i++
+		if i >= len(lines) || lines[i] != "" {
+			return nil, fmt.Errorf("siwe: expected empty line after statement")
+		}
+		i++
+	} else if i < len(lines) && lines[i] == "" {
+		i++
+	}
+
+	fields := []struct {
+		key      string
+		optional bool
+		set      func(string) error
+	}{
+		{"URI", false, func(v string) error { m.URI = v; return nil }},
+		{"Version", false, func(v string) error {
+			if v != "1" {
+				return fmt.Errorf("unsupported version %q", v)
+			}
+			m.Version = v
+			return nil
+		}},
+		{"Chain ID", false, func(v string) (err error) { m.ChainID, err = strconv.ParseInt(v, 10, 64); return err }},
+		{"Nonce", false, func(v string) error {
+			if len(v) < 8 || !isAlphanumeric(v) {
+				return fmt.Errorf("nonce must be at least 8 alphanumeric characters")
+			}
+			m.Nonce = v
+			return nil
+		}},
+		{"Issued At", false, func(v string) (err error) { m.IssuedAt, err = time.Parse(time.RFC3339, v); return err }},
+		{"Expiration Time", true, func(v string) error {
+			t, err := time.Parse(time.RFC3339, v)
+			m.ExpirationTime = &t
+			return err
+		}},
+		{"Not Before", true, func(v string) error {
+			t, err := time.Parse(time.RFC3339, v)
+			m.NotBefore = &t
+			return err
+		}},
+		{"Request ID", true, func(v string) error { m.RequestID = v; return nil }},
+	}
+	for _, f := range fields {
+		if i >= len(lines) || !strings.HasPrefix(lines[i], f.key+": ") {
+			if f.optional {
+				continue
+			}
+			return nil, fmt.Errorf("siwe: missing %q", f.key)
+		}
+		if err := f.set(strings.TrimPrefix(lines[i], f.key+": ")); err != nil {
+			return nil, fmt.Errorf("siwe: invalid %q: %w", f.key, err)
+		}
+		i++
+	}
+
+	if i < len(lines) && lines[i] == "Resources:" {
+		for i++; i < len(lines) && strings.HasPrefix(lines[i], "- "); i++ {
+			m.Resources = append(m.Resources, strings.TrimPrefix(lines[i], "- "))
+		}
+	}
+	// 끝의 줄바꿈 하나는 허용합니다.
+	if i < len(lines) && !(i == len(lines)-1 && lines[i] == "") {
+		return nil, fmt.Errorf("siwe: unexpected line %q", lines[i])
+	}
+	return m, nil
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
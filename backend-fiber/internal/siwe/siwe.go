@@ -0,0 +1,282 @@
+package siwe
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/config"
+	"github.com/returnTesha/whois/pkg/hstoken"
+)
+
+// LocalsKey - 세션 미들웨어가 확인한 지갑 주소를 저장하는 fiber Locals 키
+const LocalsKey = "wallet"
+
+// SessionLocalsKey - 세션 토큰을 구분하는 짧은 해시 (토큰 자체는 남기지 않습니다)
+const SessionLocalsKey = "sessionID"
+
+const (
+	defaultNonceTTL   = 5 * time.Minute
+	defaultSessionTTL = 24 * time.Hour
+	maxNonces         = 10000
+	clockSkew         = time.Minute
+	nonceLength       = 17
+	nonceAlphabet     = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+var (
+	// ErrInvalidMessage - 메시지 형식이나 내용(도메인, 체인, 시간)이 맞지 않음 (400)
+	ErrInvalidMessage = errors.New("invalid sign-in message")
+	// ErrInvalidSignature - 서명이 메시지의 주소와 맞지 않거나 nonce가 없음 (401)
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrTooManyNonces - 발급된 nonce가 너무 많음 (503)
+	ErrTooManyNonces = errors.New("too many pending sign-in requests")
+)
+
+// Session - 서명으로 확인된 지갑
+type Session struct {
+	Address   string    `json:"address"`
+	ChainID   int64     `json:"chain_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Service - nonce 발급, 서명 검증, 세션 토큰 발급/확인.
+// 서명은 노드 없이 ECDSA 복구로 확인하므로 컨트랙트 지갑(EIP-1271)은 지원하지 않습니다.
+type Service struct {
+	cfg        config.SIWEConfig
+	secret     []byte
+	nonceTTL   time.Duration
+	sessionTTL time.Duration
+	logger     *slog.Logger
+
+	mu     sync.Mutex
+	nonces map[string]time.Time // nonce → 만료 시각 (한 번 쓰면 지웁니다)
+}
+
+func NewService(cfg config.SIWEConfig, logger *slog.Logger) (*Service, error) {
+	if len(cfg.SessionSecret) < 32 {
+		return nil, fmt.Errorf("siwe: session_secret must be at least 32 bytes")
+	}
+	if len(cfg.Domains) == 0 {
+		return nil, fmt.Errorf("siwe: at least one domain is required")
+	}
+	nonceTTL := time.Duration(cfg.NonceTTLSec) * time.Second
+	if nonceTTL <= 0 {
+		nonceTTL = defaultNonceTTL
+	}
+	sessionTTL := time.Duration(cfg.SessionTTLMinutes) * time.Minute
+	if sessionTTL <= 0 {
+		sessionTTL = defaultSessionTTL
+	}
+
+	return &Service{
+		cfg:        cfg,
+		secret:     []byte(cfg.SessionSecret),
+		nonceTTL:   nonceTTL,
+		sessionTTL: sessionTTL,
+		logger:     logger.With("layer", "siwe"),
+		nonces:     map[string]time.Time{},
+	}, nil
+}
+
+// Nonce - 서명 메시지에 넣을 일회용 nonce
+func (s *Service) Nonce() (string, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for n, exp := range s.nonces {
+		if now.After(exp) {
+			delete(s.nonces, n)
+		}
+	}
+	if len(s.nonces) >= maxNonces {
+		return "", ErrTooManyNonces
+	}
+
+	nonce, err := randomNonce()
+	if err != nil {
+		return "", err
+	}
+	s.nonces[nonce] = now.Add(s.nonceTTL)
+	return nonce, nil
+}
+
+// Verify - 서명된 메시지를 확인하고 세션 토큰을 발급합니다.
+func (s *Service) Verify(raw, signature string) (string, Session, error) {
+	msg, err := ParseMessage(raw)
+	if err != nil {
+		return "", Session{}, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	now := time.Now()
+	if err := s.checkMessage(msg, now); err != nil {
+		return "", Session{}, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	signer, err := recoverSigner(raw, signature)
+	if err != nil || signer != msg.Address {
+		return "", Session{}, ErrInvalidSignature
+	}
+	// 서명까지 맞은 뒤에 nonce를 소비합니다. (잘못된 시도로 남의 nonce를 태우지 못하도록)
+	if !s.consumeNonce(msg.Nonce, now) {
+		return "", Session{}, fmt.Errorf("%w: unknown or used nonce", ErrInvalidSignature)
+	}
+
+	expiresAt := now.Add(s.sessionTTL)
+	if msg.ExpirationTime != nil && msg.ExpirationTime.Before(expiresAt) {
+		expiresAt = *msg.ExpirationTime
+	}
+	session := Session{Address: msg.Address.Hex(), ChainID: msg.ChainID, ExpiresAt: expiresAt}
+	token, err := hstoken.Sign(s.secret, hstoken.Claims{
+		Subject:   session.Address,
+		Issuer:    msg.Domain,
+		ChainID:   session.ChainID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", Session{}, err
+	}
+	s.logger.Info("지갑 로그인", "address", session.Address, "chainID", session.ChainID)
+	return token, session, nil
+}
+
+// Session - 세션 토큰을 확인합니다.
+func (s *Service) Session(token string) (Session, error) {
+	claims, err := hstoken.Verify(s.secret, token, time.Now())
+	if err != nil {
+		return Session{}, err
+	}
+	if !common.IsHexAddress(claims.Subject) {
+		return Session{}, hstoken.ErrMalformed
+	}
+	return Session{
+		Address:   claims.Subject,
+		ChainID:   claims.ChainID,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// CookieName - 세션 쿠키 이름
+func (s *Service) CookieName() string {
+	if s.cfg.CookieName != "" {
+		return s.cfg.CookieName
+	}
+	return "whois_session"
+}
+
+// CookieSecure - HTTPS에서만 쿠키를 보낼지
+func (s *Service) CookieSecure() bool {
+	return s.cfg.CookieSecure
+}
+
+// Middleware - 쿠키나 Authorization: Bearer 토큰이 유효하면 지갑 주소를 Locals에 넣습니다. 없어도 막지는 않습니다.
+func (s *Service) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := c.Cookies(s.CookieName())
+		if bearer, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok && strings.Count(bearer, ".") == 2 {
+			token = bearer
+		}
+		if token != "" {
+			if session, err := s.Session(token); err == nil {
+				c.Locals(LocalsKey, session.Address)
+				c.Locals(SessionLocalsKey, sessionID(token))
+			}
+		}
+		return c.Next()
+	}
+}
+
+// FromCtx - 세션으로 확인된 지갑 주소. 로그인하지 않았으면 빈 문자열
+func FromCtx(c *fiber.Ctx) string {
+	wallet, _ := c.Locals(LocalsKey).(string)
+	return wallet
+}
+
+// SessionIDFromCtx - 로그인 세션을 구분하는 값. 로그인하지 않았으면 빈 문자열
+func SessionIDFromCtx(c *fiber.Ctx) string {
+	id, _ := c.Locals(SessionLocalsKey).(string)
+	return id
+}
+
+func sessionID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// checkMessage - 우리 사이트, 허용 체인, 유효 시간에 대한 메시지인지 확인합니다.
+func (s *Service) checkMessage(msg *Message, now time.Time) error {
+	if !slices.Contains(s.cfg.Domains, msg.Domain) {
+		return fmt.Errorf("domain %q is not allowed", msg.Domain)
+	}
+	if len(s.cfg.ChainIDs) > 0 && !slices.Contains(s.cfg.ChainIDs, msg.ChainID) {
+		return fmt.Errorf("chain %d is not allowed", msg.ChainID)
+	}
+	if msg.IssuedAt.After(now.Add(clockSkew)) {
+		return fmt.Errorf("issued in the future")
+	}
+	if msg.IssuedAt.Before(now.Add(-s.nonceTTL - clockSkew)) {
+		return fmt.Errorf("message is too old")
+	}
+	if msg.ExpirationTime != nil && !msg.ExpirationTime.After(now) {
+		return fmt.Errorf("message expired")
+	}
+	if msg.NotBefore != nil && msg.NotBefore.After(now.Add(clockSkew)) {
+		return fmt.Errorf("message is not yet valid")
+	}
+	return nil
+}
+
+func (s *Service) consumeNonce(nonce string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	exp, ok := s.nonces[nonce]
+	if !ok {
+		return false
+	}
+	delete(s.nonces, nonce)
+	return now.Before(exp)
+}
+
+// recoverSigner - personal_sign(EIP-191) 서명에서 주소를 복구합니다.
+func recoverSigner(message, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be 65 bytes of hex")
+	}
+	// 지갑은 v를 27/28로 주지만 복구 함수는 0/1을 받습니다.
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+func randomNonce() (string, error) {
+	out := make([]byte, nonceLength)
+	limit := big.NewInt(int64(len(nonceAlphabet)))
+	for i := range out {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		out[i] = nonceAlphabet[n.Int64()]
+	}
+	return string(out), nil
+}
@@ -1,192 +1,433 @@
 package usecase
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
 	"log/slog"
-	"os"
-	"path/filepath"
-	"strings"
-	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/returnTesha/whois/internal/archive"
+	"github.com/returnTesha/whois/internal/canvas"
+	"github.com/returnTesha/whois/internal/challenge"
+	"github.com/returnTesha/whois/internal/clientip"
 	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/events"
+	"github.com/returnTesha/whois/internal/history"
+	"github.com/returnTesha/whois/internal/livefeed"
+	"github.com/returnTesha/whois/internal/pow"
+	"github.com/returnTesha/whois/internal/privacy"
 	"github.com/returnTesha/whois/internal/provider"
+	"github.com/returnTesha/whois/internal/ratelimit"
+	"github.com/returnTesha/whois/internal/resultcache"
+	"github.com/returnTesha/whois/internal/rewardpolicy"
+	"github.com/returnTesha/whois/internal/siwe"
+	"github.com/returnTesha/whois/internal/useragent"
+	"github.com/returnTesha/whois/pkg/dataurl"
+	"github.com/returnTesha/whois/pkg/phash"
 )
 
-const LOG_DIR = "/mnt/visit-logs"
-
 type DrawingUsecase interface {
 	// 핸들러에서 fiber context를 넘겨받아 모든 환경 정보를 수집합니다.
 	ProcessAndAnalyze(c *fiber.Ctx, req domain.DrawingRequest, traceID string, ip string, ua string, path string) (*domain.AnalysisResult, error)
+
+	// 비동기 작업용: 요청이 끝난 뒤에도 쓸 수 있도록 필요한 값을 미리 꺼내 둡니다.
+	NewSubmission(c *fiber.Ctx, req domain.DrawingRequest, traceID string, ip string, ua string, path string) *Submission
+	// Prepare - 그림 검사/정규화/해시 계산. 실패하면 기록을 남기고 검증 에러를 돌려줍니다.
+	Prepare(sub *Submission) error
+	// Analyze - 분석기 호출, 보상, 기록. progress는 단계(events.Stage*)가 바뀔 때마다 불립니다. (nil 가능)
+	Analyze(ctx context.Context, sub *Submission, progress func(stage string)) (*domain.AnalysisResult, error)
+	// Reject - Prepare 뒤에 분석을 끝내지 못했을 때(대기열 가득, 작업 panic 등) 마지막 failed 이벤트를 보내 구독자를 끝냅니다.
+	Reject(sub *Submission, reason error)
+}
+
+// Submission - fiber.Ctx 없이 분석/기록에 필요한 요청 정보
+type Submission struct {
+	Request domain.DrawingRequest
+	TraceID string
+	IP      string
+	UA      string
+	Agent   useragent.Agent
+	Referer string
+	Path    string
+	// 지갑 서명 로그인(SIWE)으로 확인된 주소. 있으면 보상 수령인이 됩니다.
+	VerifiedWallet string
+	// 지갑 세션을 구분하는 값 (보상 자격 제한 키)
+	SessionID string
+	// 작업 증명 풀이 상태 (solved | missing, 쓰지 않으면 빈 값)
+	PoW string
+
+	// Prepare가 채우는 값
+	Challenge challenge.Challenge
 }
 
 type drawingUsecase struct {
-	springProvider  provider.Provider
+	analyzer        provider.Provider // spring(REST/gRPC) 또는 gemini
 	polygonProvider provider.Provider
+	store           *history.Store
+	anonymizer      *privacy.Anonymizer
+	normalizer      *canvas.Normalizer // nil이면 받은 그림을 그대로 보냅니다.
+	drawings        *archive.Store     // nil이면 그림을 보관하지 않습니다.
+	cache           *resultcache.Cache // nil이면 매번 분석기를 호출합니다.
+	policy          *rewardpolicy.Policy
+	limiter         *ratelimit.Limiter
+	challenges      *challenge.Catalog
+	events          *events.Bus   // nil이면 진행 이벤트를 내보내지 않습니다.
+	feed            *livefeed.Hub // nil이면 공개 피드에 올리지 않습니다.
+	fallbackReward  bool          // 대체 분석기 결과로도 보상을 지급할지
 	logger          *slog.Logger
-	fileLock        sync.Mutex // 파일 쓰기 동시성 제어
 }
 
-func NewDrawingUsecase(spring provider.Provider, polygon provider.Provider, logger *slog.Logger) DrawingUsecase {
-	// 로그 디렉토리가 없으면 미리 생성합니다.
-	if err := os.MkdirAll(LOG_DIR, 0755); err != nil {
-		logger.Error("로그 디렉토리 생성 실패", "path", LOG_DIR, "error", err)
-	}
-
+func NewDrawingUsecase(analyzer provider.Provider, polygon provider.Provider, store *history.Store, anonymizer *privacy.Anonymizer, normalizer *canvas.Normalizer, drawings *archive.Store, cache *resultcache.Cache, policy *rewardpolicy.Policy, limiter *ratelimit.Limiter, challenges *challenge.Catalog, bus *events.Bus, feed *livefeed.Hub, fallbackReward bool, logger *slog.Logger) DrawingUsecase {
 	return &drawingUsecase{
-		springProvider:  spring,
+		analyzer:        analyzer,
 		polygonProvider: polygon,
+		store:           store,
+		anonymizer:      anonymizer,
+		normalizer:      normalizer,
+		drawings:        drawings,
+		cache:           cache,
+		policy:          policy,
+		limiter:         limiter,
+		challenges:      challenges,
+		events:          bus,
+		feed:            feed,
+		fallbackReward:  fallbackReward,
 		logger:          logger.With("layer", "usecase"),
 	}
 }
 
 func (u *drawingUsecase) ProcessAndAnalyze(c *fiber.Ctx, req domain.DrawingRequest, traceID string, ip string, ua string, path string) (*domain.AnalysisResult, error) {
-	u.logger.Info("분석 프로세스 시작", "traceID", traceID)
-	referer := c.Get("Referer", "Direct")
+	sub := u.NewSubmission(c, req, traceID, ip, ua, path)
+	if err := u.Prepare(sub); err != nil {
+		return nil, err
+	}
+	return u.Analyze(c.Context(), sub, nil)
+}
+
+func (u *drawingUsecase) NewSubmission(c *fiber.Ctx, req domain.DrawingRequest, traceID string, ip string, ua string, path string) *Submission {
 	if ip == "" {
-		ip = c.IP()
+		ip = clientip.FromCtx(c)
+	}
+	return &Submission{
+		Request: req,
+		TraceID: traceID,
+		IP:      ip,
+		UA:      ua,
+		// 비동기 기록 전에 요청 헤더(클라이언트 힌트 포함)로 환경 정보를 분석해 둡니다.
+		Agent:          useragent.ParseWithHints(ua, useragent.HintsFromHeaders(c.Get)),
+		Referer:        c.Get("Referer", "Direct"),
+		Path:           path,
+		VerifiedWallet: siwe.FromCtx(c),
+		SessionID:      siwe.SessionIDFromCtx(c),
+		PoW:            pow.StatusFromCtx(c),
 	}
+}
 
-	// 1. 도구(Provider) 실행 - Spring AI에게 분석 요청
-	resRaw, err := u.springProvider.Excute(c.Context(), req, traceID)
+func (u *drawingUsecase) Prepare(sub *Submission) error {
+	u.logger.Info("분석 프로세스 시작", "traceID", sub.TraceID)
+	emit := u.emitter(sub.TraceID, nil)
+	emit(events.StageReceived, nil, false)
+
+	// 잘못된 플레이어 정보는 잘못된 요청 본문과 같이 보고 기록 없이 돌려보냅니다.
+	if err := normalizePlayer(&sub.Request, sub.VerifiedWallet); err != nil {
+		emit(events.StageFailed, map[string]any{"error": err.Error()}, true)
+		return err
+	}
+	// 자정 직전에 받은 그림이 분석 중에 날짜가 바뀌어도 받은 시점의 오늘의 도전으로 봅니다.
+	ch, err := u.challenges.Resolve(sub.Request.Challenge, time.Now())
 	if err != nil {
-		u.logger.Error("도구 실행 중 에러 발생", "error", err, "traceID", traceID)
-		// 에러가 발생해도 접속 기록은 남기기 위해 비동기 호출 시 err 전달
-		go u.recordHistory(req, nil, traceID, ip, ua, referer, path, err)
-		return nil, err
+		emit(events.StageFailed, map[string]any{"error": err.Error()}, true)
+		return err
 	}
+	sub.Challenge = ch
+	sub.Request.Challenge, sub.Request.Symbol, sub.Request.Prompt = ch.ID, ch.Symbol, ch.Prompt
 
-	// 2. 결과 타입 변환
-	result, ok := resRaw.(domain.AnalysisResult)
-	if !ok {
-		errType := fmt.Errorf("도구 응답 타입 불일치")
-		go u.recordHistory(req, &result, traceID, ip, ua, referer, path, err)
-		return nil, errType
+	// 0. 그림 검사/정규화 - 빈 캔버스나 너무 큰 이미지는 분석기로 보내지 않습니다.
+	if u.normalizer != nil {
+		normalized, stats, err := u.normalizer.Normalize(sub.Request.ImageData)
+		if err != nil {
+			u.logger.Warn("그림 검증 실패", "error", err, "traceID", sub.TraceID, "width", stats.Width, "height", stats.Height, "bytes", stats.Bytes)
+			emit(events.StageFailed, map[string]any{"error": err.Error()}, true)
+			go u.recordHistory(*sub, nil, err)
+			return err
+		}
+		u.logger.Info("그림 정규화 완료", "traceID", sub.TraceID, "width", stats.Width, "height", stats.Height, "inkRatio", stats.InkRatio, "crop", stats.Crop, "bytes", stats.Bytes, "outBytes", stats.OutBytes)
+		sub.Request.ImageData = normalized
+		sub.Request.InkExtent = stats.InkExtent
 	}
+	u.fingerprintDrawing(&sub.Request, sub.TraceID)
+	emit(events.StageValidated, map[string]any{"image_hash": sub.Request.ImageHash}, false)
+	return nil
+}
 
-	//95% 이상이면
-	if result.Similarity >= 95 {
-		//go u.polygonProvider.Excute(c.Context(), result, traceID)
-		txId, _ := u.polygonProvider.Excute(c.Context(), result, traceID)
-		result.TxId = txId.(string)
+// publishAttempt - 공개 점수판에 익명화한 결과를 올립니다. (봇은 제외)
+func (u *drawingUsecase) publishAttempt(sub *Submission, result domain.AnalysisResult) {
+	if u.feed == nil || sub.Agent.IsBot {
+		return
 	}
+	u.feed.Publish(livefeed.Attempt{
+		Challenge:  result.Challenge,
+		Similarity: result.Similarity,
+		Device:     sub.Agent.Device,
+		Rewarded:   result.TxId != "",
+		Tx:         livefeed.TruncateTx(result.TxId),
+	})
+}
 
-	// 3. 기록 및 보고 (비동기로 풍부한 히스토리 저장)
-	go u.recordHistory(req, &result, traceID, ip, ua, referer, path, err)
+// Reject - 분석을 끝내지 못한 제출의 진행 스트림을 failed로 닫습니다.
+func (u *drawingUsecase) Reject(sub *Submission, reason error) {
+	u.logger.Warn("분석 작업 실패", "traceID", sub.TraceID, "error", reason)
+	u.emitter(sub.TraceID, nil)(events.StageFailed, map[string]any{"error": reason.Error()}, true)
+}
 
-	return &result, nil
+// emitter - 진행 이벤트를 버스와 progress(작업 상태)에 함께 알립니다.
+func (u *drawingUsecase) emitter(traceID string, progress func(stage string)) events.Emitter {
+	return func(stage string, data map[string]any, final bool) {
+		if progress != nil {
+			progress(stage)
+		}
+		if u.events != nil {
+			u.events.Publish(events.Event{TraceID: traceID, Stage: stage, Data: data, Final: final})
+		}
+	}
 }
 
-// recordHistory: 접속 정보, 환경 정보, AI 결과를 종합하여 파일에 저장
-func (u *drawingUsecase) recordHistory(req domain.DrawingRequest, res *domain.AnalysisResult, traceID, ip, ua, referer, path string, err error) {
-	u.fileLock.Lock()
-	defer u.fileLock.Unlock()
+func (u *drawingUsecase) Analyze(ctx context.Context, sub *Submission, progress func(stage string)) (*domain.AnalysisResult, error) {
+	req, traceID := sub.Request, sub.TraceID
+	// 보상 도구(polygon)도 같은 traceID로 진행 상황(tx_sent 등)을 알릴 수 있도록 context에 싣습니다.
+	emit := u.emitter(traceID, progress)
+	ctx = events.WithEmitter(ctx, emit)
 
-	// 여기서 더이상 c.Get()을 쓰지 않고 파라미터로 받은 값을 씁니다.
-	history := domain.AnalysisHistory{
-		Timestamp: time.Now().Format(time.RFC3339),
-		TraceID:   traceID,
-		IP:        ip,
-		UserAgent: ua,
-		Referer:   referer,
-		Path:      path,
-		Device:    u.getDeviceType(ua),
-		Browser:   u.getBrowser(ua),
-		OS:        u.getOS(ua),
-		Status:    200,
+	// 1. 같은 도전에 같은 그림의 최근 결과가 있으면 분석기를 다시 부르지 않습니다.
+	// 그림 해시를 못 구했으면 키를 비워 캐시를 쓰지 않습니다. (빈 해시끼리 결과를 나눠 쓰지 않도록)
+	cacheKey := ""
+	if req.ImageHash != "" {
+		cacheKey = req.ImageHash + "-" + req.Challenge
 	}
+	result, cached := u.cachedResult(cacheKey)
+	if cached {
+		u.logger.Info("캐시된 분석 결과 사용", "traceID", traceID, "imageHash", req.ImageHash, "similarity", result.Similarity)
+	} else {
+		// 도구(Provider) 실행 - 분석기(Spring AI 또는 Gemini)에게 분석 요청
+		emit(events.StageAnalyzing, map[string]any{"analyzer": u.analyzer.GetName()}, false)
+		resRaw, err := u.analyzer.Excute(ctx, req, traceID)
+		if err != nil {
+			u.logger.Error("도구 실행 중 에러 발생", "error", err, "traceID", traceID)
+			emit(events.StageFailed, map[string]any{"error": err.Error()}, true)
+			// 에러가 발생해도 접속 기록은 남기기 위해 비동기 호출 시 err 전달
+			go u.recordHistory(*sub, nil, err)
+			return nil, err
+		}
 
-	// 3. 결과 및 에러 데이터 매핑
-	if err != nil {
-		history.Status = 500
-		history.Error = err.Error()
-	}
-	if res != nil {
-		history.Similarity = res.Similarity
-		history.Feedback = res.Feedback
-		history.FeedbackKo = res.FeedbackKo
+		// 2. 결과 타입 변환
+		var ok bool
+		result, ok = resRaw.(domain.AnalysisResult)
+		if !ok {
+			errType := fmt.Errorf("도구 응답 타입 불일치")
+			emit(events.StageFailed, map[string]any{"error": errType.Error()}, true)
+			go u.recordHistory(*sub, nil, errType)
+			return nil, errType
+		}
+
+		// 대체 분석기의 추정 점수는 주 분석기가 돌아오면 다시 받아야 하므로 캐시하지 않습니다.
+		if u.cache != nil && !result.Fallback {
+			u.cache.Put(cacheKey, result)
+		}
 	}
+	result.Challenge = req.Challenge
+	result.Daily = sub.Challenge.Daily
+	emit(events.StageScored, map[string]any{
+		"challenge":  result.Challenge,
+		"daily":      result.Daily,
+		"similarity": result.Similarity,
+		"analyzer":   result.Analyzer,
+		"fallback":   result.Fallback,
+		"cached":     result.Cached,
+	}, false)
 
-	if res.TxId != "" {
-		history.TxId = res.TxId
+	// 도전의 기준 점수 이상이면 (대체 분석기의 추정 점수나 같은 그림 재제출로는 기본적으로 보상하지 않습니다)
+	if result.Cached {
+		u.logger.Info("재제출된 그림이라 보상을 건너뜁니다", "traceID", traceID, "imageHash", req.ImageHash)
+	} else if result.Fallback && !u.fallbackReward {
+		u.logger.Info("대체 분석 결과라 보상을 건너뜁니다", "traceID", traceID, "analyzer", result.Analyzer)
+	} else if result.Similarity >= sub.Challenge.Threshold && u.rewardAllowed(sub, &result) {
+		u.reward(ctx, req, &result, sub.Challenge.Tier, sub.VerifiedWallet, u.anonymizer.IP(sub.IP), traceID)
 	}
 
-	// 4. 파일 저장 (이전에 사용하시던 배열 추가 방식 유지)
-	u.saveToFile(history)
-}
+	// 3. 기록 및 보고 (비동기로 풍부한 히스토리 저장)
+	go u.recordHistory(*sub, &result, nil)
+	u.publishAttempt(sub, result)
 
-func (u *drawingUsecase) saveToFile(history domain.AnalysisHistory) {
-	today := time.Now().Format("2006-01-02")
-	filename := filepath.Join(LOG_DIR, fmt.Sprintf("history-%s.json", today))
+	// 전송한 트랜잭션이 있으면 확정(tx_confirmed) 이벤트가 마지막입니다.
+	emit(events.StageDone, map[string]any{"result": result}, result.TxId == "")
+	return &result, nil
+}
 
-	var histories []domain.AnalysisHistory
-	data, err := os.ReadFile(filename)
-	if err == nil {
-		json.Unmarshal(data, &histories)
+// rewardAllowed - 작업 증명과 IP/세션/지갑별 보상 자격 제한. 걸리면 판정(과 다시 받을 수 있을 때까지의 시간)을 결과에 남깁니다.
+func (u *drawingUsecase) rewardAllowed(sub *Submission, result *domain.AnalysisResult) bool {
+	if sub.PoW == pow.StatusMissing {
+		result.RewardDecision = pow.DecisionRequired
+		u.logger.Info("작업 증명 없이 보낸 그림이라 보상을 건너뜁니다", "traceID", sub.TraceID)
+		return false
 	}
-
-	histories = append(histories, history)
-
-	// 보기 편하게 Indent 적용하여 저장
-	jsonData, err := json.MarshalIndent(histories, "", "  ")
-	if err != nil {
-		u.logger.Error("JSON 마샬링 실패", "error", err)
-		return
+	if u.limiter == nil {
+		return true
 	}
-
-	if err := os.WriteFile(filename, jsonData, 0644); err != nil {
-		u.logger.Error("파일 저장 실패", "error", err)
+	ok, wait := u.limiter.Allow(ratelimit.RuleReward, ratelimit.Identity{IP: sub.IP, Session: sub.SessionID, Wallet: sub.VerifiedWallet})
+	if !ok {
+		result.RewardDecision = ratelimit.DecisionLimited
+		result.RewardRetryAfter = ratelimit.RetryAfterSeconds(wait)
+		u.logger.Info("보상 자격 제한에 걸려 보상을 건너뜁니다", "traceID", sub.TraceID, "retryAfter", result.RewardRetryAfter)
 	}
+	return ok
 }
 
-// 환경 분석 헬퍼 함수들
-func (u *drawingUsecase) getOS(ua string) string {
-	if contains(ua, "Windows") {
-		return "Windows"
+// reward - 유사 그림 정책을 거쳐 토큰을 보냅니다. 로그인한 지갑이 없으면 (가공된) IP로 수령인을 구분합니다.
+func (u *drawingUsecase) reward(ctx context.Context, req domain.DrawingRequest, result *domain.AnalysisResult, tier challenge.Tier, wallet, ip, traceID string) {
+	recipient := rewardpolicy.Recipient(ip, wallet)
+	multiplier := 1.0
+	evaluated := false
+	if u.policy != nil && req.PHash != "" {
+		if hash, err := phash.Parse(req.PHash); err == nil {
+			decision := u.policy.Evaluate(recipient, traceID, hash)
+			result.RewardDecision = decision.Action
+			result.DuplicateOf = decision.DuplicateOf
+			multiplier = decision.Multiplier
+			evaluated = decision.Action != rewardpolicy.ActionBlock
+		}
 	}
-	if contains(ua, "Mac") {
-		return "MacOS"
+	// 실제로 보낸 보상만 이후 제출의 비교 대상으로 남깁니다.
+	paid := false
+	defer func() {
+		if !evaluated {
+			return
+		}
+		if paid {
+			u.policy.Commit(traceID)
+		} else {
+			u.policy.Release(traceID)
+		}
+	}()
+	if multiplier <= 0 || u.polygonProvider == nil {
+		return
 	}
-	if contains(ua, "Linux") {
-		return "Linux"
+	events.Emit(ctx, events.StageRewardQueued, map[string]any{"decision": result.RewardDecision, "multiplier": multiplier}, false)
+
+	//go u.polygonProvider.Excute(c.Context(), result, traceID)
+	txId, err := u.polygonProvider.Excute(ctx, domain.RewardRequest{
+		Result:     *result,
+		Recipient:  wallet,
+		Multiplier: multiplier,
+		MinAmount:  tier.MinAmount,
+		MaxAmount:  tier.MaxAmount,
+	}, traceID)
+	if err != nil {
+		u.logger.Error("보상 전송 실패", "error", err, "traceID", traceID)
+		return
 	}
-	if contains(ua, "Android") {
-		return "Android"
+	result.TxId, _ = txId.(string)
+	paid = result.TxId != ""
+}
+
+// cachedResult - 캐시 적중 시 Cached 표시를 붙여 돌려줍니다.
+func (u *drawingUsecase) cachedResult(key string) (domain.AnalysisResult, bool) {
+	if u.cache == nil {
+		return domain.AnalysisResult{}, false
 	}
-	if contains(ua, "iPhone") || contains(ua, "iOS") {
-		return "iOS"
+	result, ok := u.cache.Get(key)
+	if !ok {
+		return result, false
 	}
-	return "Unknown"
+	result.Cached = true
+	result.TxId = ""
+	return result, true
 }
 
-func (u *drawingUsecase) getBrowser(ua string) string {
-	if contains(ua, "Chrome") {
-		return "Chrome"
+// fingerprintDrawing - 그림의 SHA-256/지각 해시를 계산하고 보관소에 저장합니다. 보관 실패는 분석을 막지 않습니다.
+func (u *drawingUsecase) fingerprintDrawing(req *domain.DrawingRequest, traceID string) {
+	_, raw, err := dataurl.Decode(req.ImageData)
+	if err != nil {
+		return
 	}
-	if contains(ua, "Safari") {
-		return "Safari"
+	req.ImageHash = archive.Hash(raw)
+	if img, _, err := image.Decode(bytes.NewReader(raw)); err == nil {
+		req.PHash = phash.Compute(img).String()
 	}
-	if contains(ua, "Firefox") {
-		return "Firefox"
+	if u.drawings == nil {
+		return
 	}
-	if contains(ua, "Edge") {
-		return "Edge"
+
+	if _, created, err := u.drawings.Put(raw); err != nil {
+		u.logger.Error("그림 보관 실패", "error", err, "traceID", traceID, "imageHash", req.ImageHash)
+	} else if !created {
+		u.logger.Info("이미 보관된 그림", "traceID", traceID, "imageHash", req.ImageHash)
 	}
-	return "Unknown"
 }
 
-func (u *drawingUsecase) getDeviceType(ua string) string {
-	if contains(ua, "Mobile") {
-		return "Mobile"
+// recordHistory: 접속 정보, 환경 정보, AI 결과를 종합하여 파일에 저장
+func (u *drawingUsecase) recordHistory(sub Submission, res *domain.AnalysisResult, err error) {
+	// 여기서 더이상 c.Get()을 쓰지 않고 요청 때 꺼내 둔 값을 씁니다.
+	req, agent := sub.Request, sub.Agent
+	history := domain.AnalysisHistory{
+		Timestamp:      time.Now().Format(time.RFC3339),
+		TraceID:        sub.TraceID,
+		ImageHash:      req.ImageHash,
+		PHash:          req.PHash,
+		IP:             u.anonymizer.IP(sub.IP),
+		UserAgent:      u.anonymizer.UserAgent(sub.UA, agent.Browser, agent.OS),
+		Referer:        sub.Referer,
+		Path:           sub.Path,
+		Wallet:         req.Wallet,
+		WalletVerified: sub.VerifiedWallet != "",
+		Nickname:       req.Nickname,
+		Challenge:      req.Challenge,
+		Daily:          sub.Challenge.Daily,
+		Device:         agent.Device,
+		Browser:        agent.Browser,
+		BrowserVersion: agent.BrowserVersion,
+		OS:             agent.OS,
+		OSVersion:      agent.OSVersion,
+		IsBot:          agent.IsBot,
+		BotName:        agent.BotName,
+		Status:         200,
 	}
-	if contains(ua, "Tablet") || contains(ua, "iPad") {
-		return "Tablet"
+
+	// 3. 결과 및 에러 데이터 매핑
+	if err != nil {
+		history.Status = 500
+		var invalidImage *canvas.ValidationError
+		if errors.As(err, &invalidImage) {
+			history.Status = invalidImage.Status
+		} else if errors.Is(err, provider.ErrInvalidResponse) {
+			history.Status = 502
+		}
+		history.Error = err.Error()
+	}
+	if res != nil {
+		history.Similarity = res.Similarity
+		history.Feedback = res.Feedback
+		history.FeedbackKo = res.FeedbackKo
+		history.TxId = res.TxId
+		history.Analyzer = res.Analyzer
+		history.Fallback = res.Fallback
+		history.FallbackReason = res.FallbackReason
+		history.Variant = res.Variant
+		history.Analyzers = res.Analyzers
+		history.AnalyzerScores = res.Scores
+		history.FailedAnalyzers = res.FailedAnalyzers
+		history.Cached = res.Cached
+		history.RewardDecision = res.RewardDecision
+		history.DuplicateOf = res.DuplicateOf
 	}
-	return "Desktop"
-}
 
-func contains(s, substr string) bool {
-	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+	// 4. 파일 저장 (이전에 사용하시던 배열 추가 방식 유지)
+	if err := u.store.Append(history); err != nil {
+		u.logger.Error("파일 저장 실패", "error", err)
+	}
 }
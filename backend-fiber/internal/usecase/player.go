@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/returnTesha/whois/internal/domain"
+)
+
+const maxNicknameLength = 20
+
+// ErrInvalidPlayer - 지갑 주소나 닉네임 형식이 잘못됨 (400)
+var ErrInvalidPlayer = errors.New("invalid player")
+
+// normalizePlayer - 지갑 주소는 체크섬 형식으로 맞추고, 닉네임은 앞뒤 공백을 지웁니다.
+// 지갑으로 로그인했다면(verified) 그 주소를 쓰고, 본문의 다른 주소는 거절합니다.
+func normalizePlayer(req *domain.DrawingRequest, verified string) error {
+	if wallet := strings.TrimSpace(req.Wallet); wallet != "" {
+		if !common.IsHexAddress(wallet) {
+			return fmt.Errorf("%w: wallet must be a 0x-prefixed address", ErrInvalidPlayer)
+		}
+		req.Wallet = common.HexToAddress(wallet).Hex()
+	}
+	if verified != "" {
+		if req.Wallet != "" && req.Wallet != verified {
+			return fmt.Errorf("%w: wallet does not match the signed-in account", ErrInvalidPlayer)
+		}
+		req.Wallet = verified
+	}
+
+	req.Nickname = strings.TrimSpace(req.Nickname)
+	if req.Nickname == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(req.Nickname) > maxNicknameLength {
+		return fmt.Errorf("%w: nickname must be at most %d characters", ErrInvalidPlayer, maxNicknameLength)
+	}
+	for _, r := range req.Nickname {
+		if !unicode.IsPrint(r) {
+			return fmt.Errorf("%w: nickname contains unprintable characters", ErrInvalidPlayer)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/returnTesha/whois/internal/archive"
+	"github.com/returnTesha/whois/internal/domain"
+	"github.com/returnTesha/whois/internal/history"
+	"github.com/returnTesha/whois/internal/privacy"
+)
+
+// debugImageExt - 디버그 이미지 확장자 (파일 이름은 traceID + 확장자)
+const debugImageExt = ".png"
+
+// ErrInvalidErasure - 삭제 요청이 잘못됨 (400)
+var ErrInvalidErasure = errors.New("invalid erasure request")
+
+type PrivacyUsecase interface {
+	// 삭제 요청(right-to-erasure): traceID 또는 IP(해시)에 연결된 기록과 이미지를 모두 지웁니다.
+	Erase(req domain.ErasureRequest) (*domain.ErasureReport, error)
+}
+
+type privacyUsecase struct {
+	store      *history.Store
+	anonymizer *privacy.Anonymizer
+	imageDir   string
+	drawings   *archive.Store
+	logger     *slog.Logger
+}
+
+func NewPrivacyUsecase(store *history.Store, anonymizer *privacy.Anonymizer, imageDir string, drawings *archive.Store, logger *slog.Logger) PrivacyUsecase {
+	return &privacyUsecase{
+		store:      store,
+		anonymizer: anonymizer,
+		imageDir:   imageDir,
+		drawings:   drawings,
+		logger:     logger.With("layer", "privacy"),
+	}
+}
+
+func (u *privacyUsecase) Erase(req domain.ErasureRequest) (*domain.ErasureReport, error) {
+	// truncate 모드에서는 같은 서브넷의 다른 사람 기록까지 지워지므로 IP로는 지우지 않습니다.
+	if (req.IP != "" || req.IPHash != "") && !u.anonymizer.IPIdentifies() {
+		return nil, fmt.Errorf("%w: ip_mode가 truncate라 IP로는 지울 수 없습니다. trace_id로 요청하세요", ErrInvalidErasure)
+	}
+	// 원본 IP로 요청하면 저장할 때와 같은 방식으로 가공해서 비교합니다.
+	ipKey := req.IPHash
+	if ipKey == "" && req.IP != "" {
+		ipKey = u.anonymizer.IP(req.IP)
+	}
+	if req.TraceID == "" && ipKey == "" {
+		return nil, fmt.Errorf("%w: trace_id, ip_hash 또는 ip 중 하나는 필요합니다", ErrInvalidErasure)
+	}
+
+	removed, err := u.store.Erase(func(h domain.AnalysisHistory) bool {
+		return (req.TraceID != "" && h.TraceID == req.TraceID) || (ipKey != "" && h.IP == ipKey)
+	})
+	if err != nil {
+		u.logger.Error("히스토리 삭제 실패", "error", err)
+		return nil, err
+	}
+
+	traceIDs := map[string]bool{}
+	if req.TraceID != "" {
+		traceIDs[req.TraceID] = true
+	}
+	for _, h := range removed {
+		if h.TraceID != "" {
+			traceIDs[h.TraceID] = true
+		}
+	}
+
+	report := &domain.ErasureReport{HistoryRemoved: len(removed)}
+	for traceID := range traceIDs {
+		report.TraceIDs = append(report.TraceIDs, traceID)
+		report.ImagesRemoved += u.removeImages(traceID)
+	}
+
+	report.ImagesRemoved += u.removeDrawings(removed)
+
+	u.logger.Info("삭제 요청 처리 완료", "history", report.HistoryRemoved, "images", report.ImagesRemoved)
+	return report, nil
+}
+
+// removeImages - 이미지 디렉토리에서 그 traceID의 디버그 이미지("<traceID>.png", Spring 분석 서비스가 저장)를 지웁니다.
+// 이름이 정확히 같은 파일만 지웁니다. (다른 traceID에 이 값이 들어 있을 수 있으므로)
+func (u *privacyUsecase) removeImages(traceID string) int {
+	if u.imageDir == "" {
+		return 0
+	}
+
+	entries, err := os.ReadDir(u.imageDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			u.logger.Error("이미지 디렉토리 읽기 실패", "path", u.imageDir, "error", err)
+		}
+		return 0
+	}
+
+	name := traceID + debugImageExt
+	count := 0
+	for _, e := range entries {
+		if e.IsDir() || e.Name() != name {
+			continue
+		}
+		if err := os.Remove(filepath.Join(u.imageDir, e.Name())); err != nil {
+			u.logger.Error("이미지 삭제 실패", "file", e.Name(), "error", err)
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// removeDrawings - 지운 기록이 가리키던 보관 그림 중 다른 기록이 더 이상 참조하지 않는 것만 지웁니다.
+// (같은 그림을 여러 사람이 올렸을 수 있으므로)
+func (u *privacyUsecase) removeDrawings(removed []domain.AnalysisHistory) int {
+	if u.drawings == nil {
+		return 0
+	}
+
+	orphans := map[string]bool{}
+	for _, h := range removed {
+		if h.ImageHash != "" {
+			orphans[h.ImageHash] = true
+		}
+	}
+	if len(orphans) == 0 {
+		return 0
+	}
+
+	err := u.store.Scan(func(h domain.AnalysisHistory) bool {
+		delete(orphans, h.ImageHash)
+		return len(orphans) > 0
+	})
+	if err != nil {
+		u.logger.Error("그림 참조 확인 실패", "error", err)
+		return 0
+	}
+
+	count := 0
+	for hash := range orphans {
+		ok, err := u.drawings.Remove(hash)
+		if err != nil {
+			u.logger.Error("보관 그림 삭제 실패", "imageHash", hash, "error", err)
+			continue
+		}
+		if ok {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,135 @@
+package useragent
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Hints - User-Agent Client Hints (Sec-CH-UA-*) 헤더 값. 크롬 계열만 보내고, 사파리/파이어폭스는 보내지 않습니다.
+type Hints struct {
+	UA              string // Sec-CH-UA
+	FullVersionList string // Sec-CH-UA-Full-Version-List
+	Mobile          string // Sec-CH-UA-Mobile
+	Platform        string // Sec-CH-UA-Platform
+	PlatformVersion string // Sec-CH-UA-Platform-Version
+	Model           string // Sec-CH-UA-Model
+}
+
+// HintsFromHeaders - 헤더 조회 함수(fiber의 c.Get 등)로 힌트를 모읍니다.
+func HintsFromHeaders(get func(key string, defaultValue ...string) string) Hints {
+	return Hints{
+		UA:              get("Sec-CH-UA"),
+		FullVersionList: get("Sec-CH-UA-Full-Version-List"),
+		Mobile:          get("Sec-CH-UA-Mobile"),
+		Platform:        get("Sec-CH-UA-Platform"),
+		PlatformVersion: get("Sec-CH-UA-Platform-Version"),
+		Model:           get("Sec-CH-UA-Model"),
+	}
+}
+
+// 브랜드 목록에서 구체적인 브라우저를 먼저 고릅니다. (Chromium은 최후의 선택)
+var brandPriority = []struct {
+	brand, name string
+}{
+	{"Microsoft Edge", "Edge"},
+	{"Opera", "Opera"},
+	{"Whale", "Whale"},
+	{"Samsung Internet", "Samsung Internet"},
+	{"Google Chrome", "Chrome"},
+	{"HeadlessChrome", "HeadlessChrome"},
+	{"Chromium", "Chrome"},
+}
+
+var platformNames = map[string]string{
+	"windows":   "Windows",
+	"macos":     "MacOS",
+	"android":   "Android",
+	"ios":       "iOS",
+	"chrome os": "ChromeOS",
+	"chromeos":  "ChromeOS",
+	"linux":     "Linux",
+}
+
+func (h Hints) apply(agent *Agent) {
+	brands := parseBrands(h.FullVersionList)
+	if len(brands) == 0 {
+		brands = parseBrands(h.UA)
+	}
+	for _, p := range brandPriority {
+		version, ok := brands[p.brand]
+		if !ok {
+			continue
+		}
+		if p.name == "HeadlessChrome" {
+			agent.IsBot = true
+			agent.BotName = p.name
+			break
+		}
+		agent.Browser = p.name
+		agent.BrowserVersion = version
+		break
+	}
+
+	if name, ok := platformNames[strings.ToLower(unquote(h.Platform))]; ok {
+		agent.OS = name
+		agent.OSVersion = platformVersion(name, unquote(h.PlatformVersion), agent.OSVersion)
+	}
+
+	// UA 축소(reduction) 이후 안드로이드 UA는 모델명이 "K"로 고정되므로 힌트가 더 정확합니다.
+	switch h.Mobile {
+	case "?1":
+		if agent.OS != "iPadOS" {
+			agent.Device = DeviceMobile
+		}
+	case "?0":
+		if agent.OS == "Android" {
+			agent.Device = DeviceTablet
+		}
+	}
+}
+
+// platformVersion - 윈도우는 플랫폼 버전 13 이상이 Windows 11입니다. (UA에는 항상 NT 10.0으로 나옵니다)
+func platformVersion(os, version, fallback string) string {
+	if version == "" {
+		return fallback
+	}
+	if os == "Windows" {
+		major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+		if err != nil {
+			return fallback
+		}
+		switch {
+		case major >= 13:
+			return "11"
+		case major > 0:
+			return "10"
+		}
+		return fallback
+	}
+	return version
+}
+
+// parseBrands - `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"` 형식을 파싱합니다.
+func parseBrands(header string) map[string]string {
+	brands := map[string]string{}
+	for _, item := range strings.Split(header, ",") {
+		parts := strings.Split(item, ";")
+		brand := unquote(parts[0])
+		if brand == "" || strings.Contains(strings.ToLower(brand), "brand") {
+			// GREASE 값("Not A(Brand" 등)은 무시합니다.
+			continue
+		}
+		version := ""
+		for _, param := range parts[1:] {
+			if key, value, ok := strings.Cut(strings.TrimSpace(param), "="); ok && key == "v" {
+				version = unquote(value)
+			}
+		}
+		brands[brand] = version
+	}
+	return brands
+}
+
+func unquote(s string) string {
+	return strings.Trim(strings.TrimSpace(s), `"`)
+}
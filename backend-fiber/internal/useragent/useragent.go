@@ -0,0 +1,214 @@
+package useragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 기존 히스토리와 같은 값을 쓰도록 이름을 맞춥니다.
+const (
+	DeviceDesktop = "Desktop"
+	DeviceMobile  = "Mobile"
+	DeviceTablet  = "Tablet"
+	DeviceBot     = "Bot"
+
+	Unknown = "Unknown"
+)
+
+// AcceptCH - 브라우저에 추가로 요청할 고엔트로피 클라이언트 힌트 목록
+const AcceptCH = "Sec-CH-UA-Full-Version-List, Sec-CH-UA-Platform-Version, Sec-CH-UA-Model"
+
+// Agent - User-Agent(및 클라이언트 힌트) 분석 결과
+type Agent struct {
+	Browser        string
+	BrowserVersion string
+	OS             string
+	OSVersion      string
+	Device         string
+	IsBot          bool
+	BotName        string
+}
+
+type rule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// 봇/크롤러/스크립트 클라이언트. 위에서부터 먼저 맞는 이름을 씁니다.
+var botRules = []rule{
+	{"Googlebot", regexp.MustCompile(`(?i)googlebot|google-inspectiontool|adsbot-google|mediapartners-google`)},
+	{"Bingbot", regexp.MustCompile(`(?i)bingbot|bingpreview`)},
+	{"Yeti", regexp.MustCompile(`(?i)\byeti\b`)}, // 네이버 검색
+	{"Daumoa", regexp.MustCompile(`(?i)daumoa`)},
+	{"YandexBot", regexp.MustCompile(`(?i)yandex(bot|images)`)},
+	{"Baiduspider", regexp.MustCompile(`(?i)baiduspider`)},
+	{"DuckDuckBot", regexp.MustCompile(`(?i)duckduckbot`)},
+	{"Applebot", regexp.MustCompile(`(?i)applebot`)},
+	{"GPTBot", regexp.MustCompile(`(?i)gptbot|chatgpt-user|oai-searchbot`)},
+	{"ClaudeBot", regexp.MustCompile(`(?i)claudebot|claude-web|anthropic-ai`)},
+	{"CCBot", regexp.MustCompile(`(?i)ccbot`)},
+	{"Bytespider", regexp.MustCompile(`(?i)bytespider`)},
+	{"AhrefsBot", regexp.MustCompile(`(?i)ahrefsbot`)},
+	{"SemrushBot", regexp.MustCompile(`(?i)semrushbot`)},
+	{"FacebookBot", regexp.MustCompile(`(?i)facebookexternalhit|facebookcatalog|meta-externalagent`)},
+	{"Twitterbot", regexp.MustCompile(`(?i)twitterbot`)},
+	{"Slackbot", regexp.MustCompile(`(?i)slackbot|slack-imgproxy`)},
+	{"Discordbot", regexp.MustCompile(`(?i)discordbot`)},
+	{"TelegramBot", regexp.MustCompile(`(?i)telegrambot`)},
+	{"KakaoTalk Scrap", regexp.MustCompile(`(?i)kakaotalk-scrap`)},
+	{"HeadlessChrome", regexp.MustCompile(`(?i)headlesschrome`)},
+	{"PhantomJS", regexp.MustCompile(`(?i)phantomjs`)},
+	{"curl", regexp.MustCompile(`(?i)^curl/`)},
+	{"Wget", regexp.MustCompile(`(?i)^wget/`)},
+	{"python-requests", regexp.MustCompile(`(?i)python-requests|python-urllib|aiohttp|httpx`)},
+	{"Go-http-client", regexp.MustCompile(`(?i)go-http-client`)},
+	{"okhttp", regexp.MustCompile(`(?i)^okhttp/`)},
+	{"Java", regexp.MustCompile(`(?i)^java/|apache-httpclient`)},
+	{"axios", regexp.MustCompile(`(?i)^axios/|node-fetch|undici`)},
+	{"Postman", regexp.MustCompile(`(?i)postmanruntime|insomnia`)},
+	{"Scrapy", regexp.MustCompile(`(?i)scrapy`)},
+	{"Generic crawler", regexp.MustCompile(`(?i)bot/|\bbot\b|crawler|spider|scraper`)},
+}
+
+// 브라우저. 크롬 기반 브라우저가 Chrome 토큰을 같이 보내므로 순서가 중요합니다.
+var browserRules = []rule{
+	{"Edge", regexp.MustCompile(`(?:Edg|EdgA|EdgiOS|Edge)/([\d.]+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|OPiOS|Opera)/([\d.]+)`)},
+	{"Whale", regexp.MustCompile(`Whale/([\d.]+)`)},
+	{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/([\d.]+)`)},
+	{"KakaoTalk", regexp.MustCompile(`KAKAOTALK(?:/| )([\d.]+)`)},
+	{"Naver", regexp.MustCompile(`NAVER\((?:inapp|higgs)[^)]*\)`)},
+	{"Instagram", regexp.MustCompile(`Instagram ([\d.]+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/([\d.]+)`)},
+	{"Chrome", regexp.MustCompile(`(?:CriOS|Chrome)/([\d.]+)`)},
+	{"Safari", regexp.MustCompile(`Version/([\d.]+).*Safari/`)},
+	{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)([\d.]+)`)},
+}
+
+var (
+	reWindows  = regexp.MustCompile(`Windows NT ([\d.]+)`)
+	reIOS      = regexp.MustCompile(`(?:iPhone|CPU) OS ([\d_]+)`)
+	reAndroid  = regexp.MustCompile(`Android ([\d.]+)`)
+	reMacOS    = regexp.MustCompile(`Mac OS X ([\d_.]+)`)
+	reChromeOS = regexp.MustCompile(`CrOS \S+ ([\d.]+)`)
+)
+
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// Parse - User-Agent 문자열만으로 분석합니다.
+func Parse(ua string) Agent {
+	return ParseWithHints(ua, Hints{})
+}
+
+// ParseWithHints - UA 문자열로 분석한 뒤, 클라이언트 힌트가 있으면 더 정확한 값으로 덮어씁니다.
+func ParseWithHints(ua string, hints Hints) Agent {
+	ua = strings.TrimSpace(ua)
+	agent := Agent{
+		Browser: Unknown,
+		OS:      Unknown,
+		Device:  DeviceDesktop,
+	}
+
+	if ua == "" {
+		agent.IsBot = true
+		agent.BotName = "Empty user agent"
+		agent.Device = DeviceBot
+		return agent
+	}
+
+	for _, r := range botRules {
+		if r.re.MatchString(ua) {
+			agent.IsBot = true
+			agent.BotName = r.name
+			break
+		}
+	}
+
+	for _, r := range browserRules {
+		if m := r.re.FindStringSubmatch(ua); m != nil {
+			agent.Browser = r.name
+			if len(m) > 1 {
+				agent.BrowserVersion = m[1]
+			}
+			break
+		}
+	}
+
+	agent.OS, agent.OSVersion = parseOS(ua)
+	agent.Device = parseDevice(ua, agent.OS)
+
+	hints.apply(&agent)
+
+	if agent.IsBot {
+		agent.Device = DeviceBot
+	}
+	return agent
+}
+
+func parseOS(ua string) (string, string) {
+	switch {
+	case strings.Contains(ua, "Windows Phone"):
+		return "Windows Phone", ""
+	case strings.Contains(ua, "Windows"):
+		if m := reWindows.FindStringSubmatch(ua); m != nil {
+			if v, ok := windowsVersions[m[1]]; ok {
+				return "Windows", v
+			}
+			return "Windows", m[1]
+		}
+		return "Windows", ""
+	// iPad는 UA에 "iPhone OS"가 아니라 "CPU OS"가 들어갑니다. (Mobile 토큰도 같이 옵니다)
+	case strings.Contains(ua, "iPad"):
+		return "iPadOS", iosVersion(ua)
+	case strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPod"):
+		return "iOS", iosVersion(ua)
+	// 안드로이드 UA에는 Linux 토큰도 있으므로 Linux보다 먼저 확인합니다.
+	case strings.Contains(ua, "Android"):
+		if m := reAndroid.FindStringSubmatch(ua); m != nil {
+			return "Android", m[1]
+		}
+		return "Android", ""
+	case strings.Contains(ua, "CrOS"):
+		if m := reChromeOS.FindStringSubmatch(ua); m != nil {
+			return "ChromeOS", m[1]
+		}
+		return "ChromeOS", ""
+	case strings.Contains(ua, "Macintosh") || strings.Contains(ua, "Mac OS X"):
+		if m := reMacOS.FindStringSubmatch(ua); m != nil {
+			return "MacOS", strings.ReplaceAll(m[1], "_", ".")
+		}
+		return "MacOS", ""
+	case strings.Contains(ua, "Linux"):
+		return "Linux", ""
+	}
+	return Unknown, ""
+}
+
+func iosVersion(ua string) string {
+	if m := reIOS.FindStringSubmatch(ua); m != nil {
+		return strings.ReplaceAll(m[1], "_", ".")
+	}
+	return ""
+}
+
+func parseDevice(ua, os string) string {
+	lower := strings.ToLower(ua)
+	switch {
+	// 태블릿을 먼저 봐야 "Mobile" 토큰이 붙은 iPad가 모바일로 잡히지 않습니다.
+	case os == "iPadOS", strings.Contains(lower, "tablet"), strings.Contains(lower, "kindle"), strings.Contains(lower, "silk/"):
+		return DeviceTablet
+	// 안드로이드 태블릿은 Mobile 토큰을 보내지 않습니다.
+	case os == "Android" && !strings.Contains(ua, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(ua, "Mobi"), os == "iOS", os == "Windows Phone":
+		return DeviceMobile
+	}
+	return DeviceDesktop
+}
@@ -0,0 +1,140 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// ErrOpen - 회로가 열려 있어서 호출하지 않고 바로 실패시킨 경우
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Stats - 헬스체크/메트릭으로 내보내는 값
+type Stats struct {
+	State               State     `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Requests            uint64    `json:"requests"`
+	Failures            uint64    `json:"failures"`
+	Rejected            uint64    `json:"rejected"`
+	OpenedAt            time.Time `json:"opened_at,omitzero"`
+}
+
+// Breaker - 연속 실패가 threshold에 닿으면 openFor 동안 호출을 막고, 그 뒤 한 번만 시험 호출(half-open)을 허용합니다.
+type Breaker struct {
+	threshold int
+	openFor   time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+
+	requests uint64
+	failed   uint64
+	rejected uint64
+}
+
+func New(threshold int, openFor time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if openFor <= 0 {
+		openFor = 30 * time.Second
+	}
+	return &Breaker{
+		threshold: threshold,
+		openFor:   openFor,
+		now:       time.Now,
+		state:     StateClosed,
+	}
+}
+
+// Allow - 호출해도 되는지 확인합니다. 허용되면 반드시 Done으로 결과를 알려야 합니다.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.openFor {
+		b.state = StateHalfOpen
+		b.probing = false
+	}
+
+	switch b.state {
+	case StateOpen:
+		b.rejected++
+		return ErrOpen
+	case StateHalfOpen:
+		// 시험 호출은 한 번에 하나만
+		if b.probing {
+			b.rejected++
+			return ErrOpen
+		}
+		b.probing = true
+	}
+
+	b.requests++
+	return nil
+}
+
+// Done - 호출 결과를 기록합니다. success=false는 서버 쪽 장애(연결 실패, 5xx)만 넘겨야 합니다.
+func (b *Breaker) Done(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state = StateClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failed++
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
+
+// Release - 결과를 판단할 수 없는 호출(클라이언트 취소 등)을 상태 변화 없이 끝냅니다.
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *Breaker) State() State {
+	return b.Stats().State
+}
+
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state
+	if state == StateOpen && b.now().Sub(b.openedAt) >= b.openFor {
+		state = StateHalfOpen
+	}
+	stats := Stats{
+		State:               state,
+		ConsecutiveFailures: b.failures,
+		Requests:            b.requests,
+		Failures:            b.failed,
+		Rejected:            b.rejected,
+	}
+	if state != StateClosed {
+		stats.OpenedAt = b.openedAt
+	}
+	return stats
+}
@@ -0,0 +1,47 @@
+package dataurl
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// DefaultMime - data URL 헤더 없이 base64만 온 경우 (캔버스 toDataURL 기본값)
+const DefaultMime = "image/png"
+
+// Decode - "data:image/png;base64,...." 또는 순수 base64 문자열을 바이트로 풉니다.
+func Decode(s string) (string, []byte, error) {
+	mime := DefaultMime
+	payload := strings.TrimSpace(s)
+
+	if strings.HasPrefix(payload, "data:") {
+		header, body, ok := strings.Cut(payload, ",")
+		if !ok {
+			return "", nil, fmt.Errorf("dataurl: missing ',' separator")
+		}
+		header = strings.TrimPrefix(header, "data:")
+		if !strings.HasSuffix(header, ";base64") {
+			return "", nil, fmt.Errorf("dataurl: only base64 data URLs are supported")
+		}
+		if m := strings.TrimSuffix(header, ";base64"); m != "" {
+			mime = m
+		}
+		payload = body
+	}
+
+	// Spring 쪽과 동일하게 공백/줄바꿈은 무시합니다.
+	payload = strings.Join(strings.Fields(payload), "")
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		// 패딩 없이 오는 경우도 허용
+		if data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "=")); err != nil {
+			return "", nil, fmt.Errorf("dataurl: invalid base64: %w", err)
+		}
+	}
+	return mime, data, nil
+}
+
+// Encode - 바이트를 data URL 문자열로 만듭니다.
+func Encode(mime string, data []byte) string {
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
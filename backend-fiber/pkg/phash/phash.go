@@ -0,0 +1,100 @@
+package phash
+
+import (
+	"fmt"
+	"image"
+	"math/bits"
+	"strconv"
+)
+
+// Hash - 지각 해시(perceptual hash) 한 쌍. 비슷하게 생긴 그림일수록 비트 차이(Hamming distance)가 작습니다.
+type Hash struct {
+	A uint64 // average hash: 8x8 축소 후 평균보다 어두운 칸
+	D uint64 // difference hash: 9x8 축소 후 가로로 이웃한 칸보다 어두운지
+}
+
+// Compute - 그림의 aHash/dHash를 계산합니다.
+func Compute(img image.Image) Hash {
+	return Hash{A: averageHash(img), D: differenceHash(img)}
+}
+
+// Distance - 두 해시 중 더 많이 다른 쪽의 비트 수 (둘 다 가까워야 같은 그림으로 봅니다)
+func Distance(a, b Hash) int {
+	return max(bits.OnesCount64(a.A^b.A), bits.OnesCount64(a.D^b.D))
+}
+
+// String - 히스토리에 남기는 형식 "aHash:dHash" (각 16자리 hex)
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x:%016x", h.A, h.D)
+}
+
+// Parse - String()의 역
+func Parse(s string) (Hash, error) {
+	if len(s) != 33 || s[16] != ':' {
+		return Hash{}, fmt.Errorf("phash: invalid hash %q", s)
+	}
+	a, err := strconv.ParseUint(s[:16], 16, 64)
+	if err != nil {
+		return Hash{}, fmt.Errorf("phash: %w", err)
+	}
+	d, err := strconv.ParseUint(s[17:], 16, 64)
+	if err != nil {
+		return Hash{}, fmt.Errorf("phash: %w", err)
+	}
+	return Hash{A: a, D: d}, nil
+}
+
+func averageHash(img image.Image) uint64 {
+	cells := shrink(img, 8, 8)
+	sum := 0.0
+	for _, v := range cells {
+		sum += v
+	}
+	mean := sum / float64(len(cells))
+
+	var h uint64
+	for i, v := range cells {
+		if v < mean {
+			h |= 1 << uint(i)
+		}
+	}
+	return h
+}
+
+func differenceHash(img image.Image) uint64 {
+	cells := shrink(img, 9, 8)
+	var h uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if cells[y*9+x] < cells[y*9+x+1] {
+				h |= 1 << uint(y*8+x)
+			}
+		}
+	}
+	return h
+}
+
+// shrink - 영역 평균으로 w x h 밝기(0~255) 격자를 만듭니다.
+func shrink(img image.Image, w, h int) []float64 {
+	b := img.Bounds()
+	sums := make([]float64, w*h)
+	counts := make([]int, w*h)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		cy := (y - b.Min.Y) * h / b.Dy()
+		for x := b.Min.X; x < b.Max.X; x++ {
+			cx := (x - b.Min.X) * w / b.Dx()
+			r, g, bl, a := img.At(x, y).RGBA()
+			// 투명 영역은 흰 배경으로 봅니다. (premultiplied)
+			luma := (299*(r>>8) + 587*(g>>8) + 114*(bl>>8)) / 1000
+			sums[cy*w+cx] += float64(luma + (0xff - a>>8))
+			counts[cy*w+cx]++
+		}
+	}
+	for i := range sums {
+		if counts[i] > 0 {
+			sums[i] /= float64(counts[i])
+		}
+	}
+	return sums
+}
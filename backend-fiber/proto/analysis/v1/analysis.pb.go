@@ -0,0 +1,246 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: analysis/v1/analysis.proto
+
+package analysisv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AnalyzeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 디코딩된 이미지 바이트 (base64 data URL이 아님)
+	Image []byte `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	// 예: "image/png"
+	MimeType string `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	TraceId  string `protobuf:"bytes,3,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	// 도전 ID (예: "question-mark"). 비어 있으면 물음표
+	Challenge string `protobuf:"bytes,4,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	// 목표 기호 (예: "?")
+	Symbol string `protobuf:"bytes,5,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// 분석기에 넘기는 목표 설명 (예: "a question mark symbol '?'")
+	Prompt        string `protobuf:"bytes,6,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeRequest) Reset() {
+	*x = AnalyzeRequest{}
+	mi := &file_analysis_v1_analysis_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeRequest) ProtoMessage() {}
+
+func (x *AnalyzeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_v1_analysis_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeRequest) Descriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AnalyzeRequest) GetImage() []byte {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *AnalyzeRequest) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+type AnalyzeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 0 ~ 100
+	Similarity    float64 `protobuf:"fixed64,1,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	Feedback      string  `protobuf:"bytes,2,opt,name=feedback,proto3" json:"feedback,omitempty"`
+	FeedbackKo    string  `protobuf:"bytes,3,opt,name=feedback_ko,json=feedbackKo,proto3" json:"feedback_ko,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeResponse) Reset() {
+	*x = AnalyzeResponse{}
+	mi := &file_analysis_v1_analysis_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeResponse) ProtoMessage() {}
+
+func (x *AnalyzeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analysis_v1_analysis_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzeResponse) Descriptor() ([]byte, []int) {
+	return file_analysis_v1_analysis_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AnalyzeResponse) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+func (x *AnalyzeResponse) GetFeedback() string {
+	if x != nil {
+		return x.Feedback
+	}
+	return ""
+}
+
+func (x *AnalyzeResponse) GetFeedbackKo() string {
+	if x != nil {
+		return x.FeedbackKo
+	}
+	return ""
+}
+
+var File_analysis_v1_analysis_proto protoreflect.FileDescriptor
+
+const file_analysis_v1_analysis_proto_rawDesc = "" +
+	"\n" +
+	"\x1aanalysis/v1/analysis.proto\x12\vanalysis.v1\"\xac\x01\n" +
+	"\x0eAnalyzeRequest\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\fR\x05image\x12\x1b\n" +
+	"\tmime_type\x18\x02 \x01(\tR\bmimeType\x12\x19\n" +
+	"\btrace_id\x18\x03 \x01(\tR\atraceId\x12\x1c\n" +
+	"\tchallenge\x18\x04 \x01(\tR\tchallenge\x12\x16\n" +
+	"\x06symbol\x18\x05 \x01(\tR\x06symbol\x12\x16\n" +
+	"\x06prompt\x18\x06 \x01(\tR\x06prompt\"n\n" +
+	"\x0fAnalyzeResponse\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x01 \x01(\x01R\n" +
+	"similarity\x12\x1a\n" +
+	"\bfeedback\x18\x02 \x01(\tR\bfeedback\x12\x1f\n" +
+	"\vfeedback_ko\x18\x03 \x01(\tR\n" +
+	"feedbackKo2^\n" +
+	"\x16DrawingAnalysisService\x12D\n" +
+	"\aAnalyze\x12\x1b.analysis.v1.AnalyzeRequest\x1a\x1c.analysis.v1.AnalyzeResponseBd\n" +
+	"%lol.valuechain.ai_gw.grpc.analysis.v1P\x01Z9github.com/returnTesha/whois/proto/analysis/v1;analysisv1b\x06proto3"
+
+var (
+	file_analysis_v1_analysis_proto_rawDescOnce sync.Once
+	file_analysis_v1_analysis_proto_rawDescData []byte
+)
+
+func file_analysis_v1_analysis_proto_rawDescGZIP() []byte {
+	file_analysis_v1_analysis_proto_rawDescOnce.Do(func() {
+		file_analysis_v1_analysis_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_analysis_v1_analysis_proto_rawDesc), len(file_analysis_v1_analysis_proto_rawDesc)))
+	})
+	return file_analysis_v1_analysis_proto_rawDescData
+}
+
+var file_analysis_v1_analysis_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_analysis_v1_analysis_proto_goTypes = []any{
+	(*AnalyzeRequest)(nil),  // 0: analysis.v1.AnalyzeRequest
+	(*AnalyzeResponse)(nil), // 1: analysis.v1.AnalyzeResponse
+}
+var file_analysis_v1_analysis_proto_depIdxs = []int32{
+	0, // 0: analysis.v1.DrawingAnalysisService.Analyze:input_type -> analysis.v1.AnalyzeRequest
+	1, // 1: analysis.v1.DrawingAnalysisService.Analyze:output_type -> analysis.v1.AnalyzeResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_analysis_v1_analysis_proto_init() }
+func file_analysis_v1_analysis_proto_init() {
+	if File_analysis_v1_analysis_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_analysis_v1_analysis_proto_rawDesc), len(file_analysis_v1_analysis_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_analysis_v1_analysis_proto_goTypes,
+		DependencyIndexes: file_analysis_v1_analysis_proto_depIdxs,
+		MessageInfos:      file_analysis_v1_analysis_proto_msgTypes,
+	}.Build()
+	File_analysis_v1_analysis_proto = out.File
+	file_analysis_v1_analysis_proto_goTypes = nil
+	file_analysis_v1_analysis_proto_depIdxs = nil
+}
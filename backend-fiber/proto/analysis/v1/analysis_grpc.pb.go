@@ -0,0 +1,128 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: analysis/v1/analysis.proto
+
+package analysisv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DrawingAnalysisService_Analyze_FullMethodName = "/analysis.v1.DrawingAnalysisService/Analyze"
+)
+
+// DrawingAnalysisServiceClient is the client API for DrawingAnalysisService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// 게이트웨이(Go) ↔ 분석 백엔드(Spring AI) 사이의 그림 분석 서비스
+type DrawingAnalysisServiceClient interface {
+	// 손글씨 그림이 목표 기호(기본 '?')와 얼마나 닮았는지 분석합니다.
+	Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error)
+}
+
+type drawingAnalysisServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDrawingAnalysisServiceClient(cc grpc.ClientConnInterface) DrawingAnalysisServiceClient {
+	return &drawingAnalysisServiceClient{cc}
+}
+
+func (c *drawingAnalysisServiceClient) Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnalyzeResponse)
+	err := c.cc.Invoke(ctx, DrawingAnalysisService_Analyze_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DrawingAnalysisServiceServer is the server API for DrawingAnalysisService service.
+// All implementations must embed UnimplementedDrawingAnalysisServiceServer
+// for forward compatibility.
+//
+// 게이트웨이(Go) ↔ 분석 백엔드(Spring AI) 사이의 그림 분석 서비스
+type DrawingAnalysisServiceServer interface {
+	// 손글씨 그림이 목표 기호(기본 '?')와 얼마나 닮았는지 분석합니다.
+	Analyze(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error)
+	mustEmbedUnimplementedDrawingAnalysisServiceServer()
+}
+
+// UnimplementedDrawingAnalysisServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDrawingAnalysisServiceServer struct{}
+
+func (UnimplementedDrawingAnalysisServiceServer) Analyze(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Analyze not implemented")
+}
+func (UnimplementedDrawingAnalysisServiceServer) mustEmbedUnimplementedDrawingAnalysisServiceServer() {
+}
+func (UnimplementedDrawingAnalysisServiceServer) testEmbeddedByValue() {}
+
+// UnsafeDrawingAnalysisServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DrawingAnalysisServiceServer will
+// result in compilation errors.
+type UnsafeDrawingAnalysisServiceServer interface {
+	mustEmbedUnimplementedDrawingAnalysisServiceServer()
+}
+
+func RegisterDrawingAnalysisServiceServer(s grpc.ServiceRegistrar, srv DrawingAnalysisServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDrawingAnalysisServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DrawingAnalysisService_ServiceDesc, srv)
+}
+
+func _DrawingAnalysisService_Analyze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DrawingAnalysisServiceServer).Analyze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DrawingAnalysisService_Analyze_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DrawingAnalysisServiceServer).Analyze(ctx, req.(*AnalyzeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DrawingAnalysisService_ServiceDesc is the grpc.ServiceDesc for DrawingAnalysisService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DrawingAnalysisService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "analysis.v1.DrawingAnalysisService",
+	HandlerType: (*DrawingAnalysisServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Analyze",
+			Handler:    _DrawingAnalysisService_Analyze_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "analysis/v1/analysis.proto",
+}